@@ -0,0 +1,30 @@
+package validator
+
+// progressSteps is roughly how many times ValidateAllProgress reports
+// progress, however many values it validates.
+const progressSteps = 100
+
+// ValidateAllProgress validates each of values like ValidateStruct, one after
+// the other, returning their errors in the same order with nil for values
+// that passed. onProgress, when not nil, is called with the number of values
+// done so far about every hundredth of the batch, and always once at the end
+// with done == total.
+func (v *Validator) ValidateAllProgress(values []any, onProgress func(done, total int)) []error {
+	total := len(values)
+	step := max(1, total/progressSteps)
+
+	errs := make([]error, total)
+	for i, value := range values {
+		errs[i] = v.ValidateStruct(value)
+
+		if done := i + 1; onProgress != nil && (done%step == 0 || done == total) {
+			onProgress(done, total)
+		}
+	}
+
+	if total == 0 && onProgress != nil {
+		onProgress(0, 0)
+	}
+
+	return errs
+}
@@ -0,0 +1,64 @@
+package validator
+
+import "testing"
+
+func TestValidateAllProgress(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		wantCalls int
+	}{
+		{"empty", 0, 1},
+		{"small", 3, 3},
+		{"hundred", 100, 100},
+		{"large", 1000, 100},
+		{"uneven", 251, 126},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values := make([]any, tt.total)
+			for i := range values {
+				values[i] = sharedCity{City: "Paris"}
+			}
+			if tt.total > 0 {
+				values[tt.total-1] = sharedCity{}
+			}
+
+			var calls [][2]int
+			errs := New().ValidateAllProgress(values, func(done, total int) {
+				calls = append(calls, [2]int{done, total})
+			})
+
+			if len(errs) != tt.total {
+				t.Fatalf("got %d errors, want %d", len(errs), tt.total)
+			}
+			for i, err := range errs {
+				if (err != nil) != (i == tt.total-1) {
+					t.Errorf("errs[%d] = %v", i, err)
+				}
+			}
+
+			if len(calls) != tt.wantCalls {
+				t.Errorf("onProgress called %d times, want %d", len(calls), tt.wantCalls)
+			}
+			for i, call := range calls {
+				if call[1] != tt.total {
+					t.Errorf("call %d: total = %d, want %d", i, call[1], tt.total)
+				}
+				if i > 0 && call[0] <= calls[i-1][0] {
+					t.Errorf("call %d: done = %d after %d", i, call[0], calls[i-1][0])
+				}
+			}
+			if last := calls[len(calls)-1]; last[0] != tt.total {
+				t.Errorf("last call done = %d, want %d", last[0], tt.total)
+			}
+		})
+	}
+
+	t.Run("nil callback", func(t *testing.T) {
+		if errs := New().ValidateAllProgress([]any{sharedCity{}}, nil); len(errs) != 1 || errs[0] == nil {
+			t.Errorf("errs = %v", errs)
+		}
+	})
+}
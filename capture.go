@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Capture describes a failed validation for reproducing customer reports
+// without logging whole payloads.
+type Capture struct {
+	Type     string
+	Failures []CapturedFailure
+}
+
+// CapturedFailure is one failure in a Capture. Params holds the rule
+// parameters without the validated value; Excerpt holds a shortened form of
+// that value when excerpts are enabled and the field is not sensitive.
+type CapturedFailure struct {
+	Field   string
+	Rule    string
+	Params  []any
+	Excerpt string
+}
+
+// WithCapture calls fn once for every validation that fails. It is never
+// called when validation succeeds.
+func WithCapture(fn func(Capture)) Option {
+	return func(cfg *Config) {
+		cfg.Capture = fn
+	}
+}
+
+// WithCaptureExcerpts includes the first and last n runes of failing values
+// in captures. Fields tagged sensitive:"true", or marked with
+// ValidationContext.Sensitive, are never excerpted.
+func WithCaptureExcerpts(n int) Option {
+	return func(cfg *Config) {
+		cfg.CaptureExcerpts = n
+	}
+}
+
+// Sensitive marks the current field as sensitive until the next call to
+// Field, so its value never appears in captures.
+func (ctx *ValidationContext) Sensitive() *ValidationContext {
+	ctx.sensitive = true
+
+	return ctx
+}
+
+// finish returns the result of ctx, reporting it to the capture callback if
+// validation failed.
+func (v *Validator) finish(ctx *ValidationContext, value any) error {
+	err := ctx.Err()
+	if err == nil || v.capture == nil {
+		return err
+	}
+
+	capture := Capture{Type: fmt.Sprintf("%T", value)}
+	for _, e := range flattenErrors(err) {
+		var failure CapturedFailure
+
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			failure.Field = fe.Field
+		}
+
+		var re *RuleError
+		if errors.As(e, &re) {
+			failure.Rule = re.Rule
+			failure.Params = withoutSubject(re.Rule, re.Params)
+			failure.Excerpt = re.excerpt
+		}
+
+		capture.Failures = append(capture.Failures, failure)
+	}
+
+	v.capture(capture)
+	return err
+}
+
+// captureDetails excerpts the validated value of a failed check. It only
+// runs for failures and only when a capture callback is configured.
+func (ctx *ValidationContext) captureDetails(re *RuleError) {
+	v := ctx.validator
+	if v.capture == nil || v.excerptRunes <= 0 || re.sensitive || len(re.Params) == 0 {
+		return
+	}
+
+	re.excerpt = excerpt(fmt.Sprint(subjectOf(re.Rule, re.Params)), v.excerptRunes)
+}
+
+// subjectOf returns the validated value among the parameters of a check.
+func subjectOf(ruleName string, params []any) any {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if subjectFirstRules[ruleName] {
+		return params[0]
+	}
+
+	return params[len(params)-1]
+}
+
+// withoutSubject drops the validated value from the parameters of a check.
+func withoutSubject(ruleName string, params []any) []any {
+	if len(params) == 0 {
+		return nil
+	}
+
+	if subjectFirstRules[ruleName] {
+		return append([]any(nil), params[1:]...)
+	}
+
+	return append([]any(nil), params[:len(params)-1]...)
+}
+
+// excerpt keeps the first and last n runes of s, eliding the middle.
+func excerpt(s string, n int) string {
+	if utf8.RuneCountInString(s) <= 2*n {
+		return s
+	}
+
+	runes := []rune(s)
+	return string(runes[:n]) + "…" + string(runes[len(runes)-n:])
+}
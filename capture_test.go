@@ -0,0 +1,106 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+type captureSignup struct {
+	Name     string `validate:"minLength=5"`
+	Bio      string `validate:"maxLength=10"`
+	Password string `validate:"minLength=12" sensitive:"true"`
+	Age      int    `validate:"greaterThan=17"`
+}
+
+func TestCapture(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		value captureSignup
+		want  []CapturedFailure
+	}{
+		{"valid value is not captured", []Option{WithCollectAll(), WithCaptureExcerpts(3)},
+			captureSignup{Name: "Ada Lovelace", Bio: "math", Password: "correct horse battery", Age: 36}, nil},
+		{"without excerpts", []Option{WithCollectAll()},
+			captureSignup{Name: "Ada", Bio: "math", Password: "correct horse battery", Age: 12},
+			[]CapturedFailure{
+				{Field: "Name", Rule: "minLength", Params: []any{5}},
+				{Field: "Age", Rule: "greaterThan", Params: []any{17}},
+			}},
+		{"short values are kept whole", []Option{WithCollectAll(), WithCaptureExcerpts(3)},
+			captureSignup{Name: "Ada", Bio: "math", Password: "correct horse battery", Age: 12},
+			[]CapturedFailure{
+				{Field: "Name", Rule: "minLength", Params: []any{5}, Excerpt: "Ada"},
+				{Field: "Age", Rule: "greaterThan", Params: []any{17}, Excerpt: "12"},
+			}},
+		{"long values are truncated", []Option{WithCollectAll(), WithCaptureExcerpts(3)},
+			captureSignup{Name: "Ada Lovelace", Bio: "mathematician and writer", Password: "correct horse battery", Age: 36},
+			[]CapturedFailure{
+				{Field: "Bio", Rule: "maxLength", Params: []any{10}, Excerpt: "mat…ter"},
+			}},
+		{"sensitive fields are redacted", []Option{WithCollectAll(), WithCaptureExcerpts(3)},
+			captureSignup{Name: "Ada Lovelace", Bio: "math", Password: "hunter2", Age: 36},
+			[]CapturedFailure{
+				{Field: "Password", Rule: "minLength", Params: []any{12}},
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var captures []Capture
+			v := New(append(tt.opts, WithCapture(func(c Capture) { captures = append(captures, c) }))...)
+			_ = v.ValidateStruct(tt.value)
+
+			if tt.want == nil {
+				if len(captures) != 0 {
+					t.Fatalf("captured %+v for a valid value", captures)
+				}
+				return
+			}
+
+			if len(captures) != 1 {
+				t.Fatalf("got %d captures, want 1", len(captures))
+			}
+			if captures[0].Type != "validator.captureSignup" {
+				t.Errorf("Type = %q", captures[0].Type)
+			}
+			if !reflect.DeepEqual(captures[0].Failures, tt.want) {
+				t.Errorf("failures = %+v, want %+v", captures[0].Failures, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaptureSensitiveContext(t *testing.T) {
+	var got Capture
+	v := New(WithCaptureExcerpts(3), WithCapture(func(c Capture) { got = c }))
+	RegisterType(v, func(s captureSignup, ctx *ValidationContext) {
+		ctx.Field("Token").Sensitive().Check("minLength", "abcdefghij", 20)
+	})
+
+	if err := v.ValidateStruct(captureSignup{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if len(got.Failures) == 0 || got.Failures[0].Field != "Token" || got.Failures[0].Excerpt != "" {
+		t.Errorf("failures = %+v, want Token without an excerpt", got.Failures)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{"abcdef", 3, "abcdef"},
+		{"abcdefg", 3, "abc…efg"},
+		{"日本語のテキスト", 2, "日本…スト"},
+		{"", 2, ""},
+	}
+
+	for _, tt := range tests {
+		if got := excerpt(tt.s, tt.n); got != tt.want {
+			t.Errorf("excerpt(%q, %d) = %q, want %q", tt.s, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package validator
+
+// Child returns a validator that inherits from v. Lookups try the child
+// first and then v, so registrations on the child shadow v's without
+// changing it, while registrations made on v later are visible to the child:
+// delegation is live, not a copy. Chains of children work the same way at
+// every level.
+//
+// What is inherited: rules (a rule registered or removed on v, after its
+// built-ins, takes precedence over the child's built-ins), type and scenario
+// handlers, formats, units, enums, hash verifiers, messages, translations
+// and the message resolver. v's middleware wraps the child's. The child
+// starts with v's configuration; grandfathered rules are not inherited.
+func (v *Validator) Child() *Validator {
+	c := newValidator(v.cfg)
+	c.parent = v
+
+	return c
+}
@@ -0,0 +1,102 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+type childOrder struct {
+	ID string
+}
+
+func TestChild(t *testing.T) {
+	parentFailure := func(params []any) error { return errors.New("parent rule") }
+	childFailure := func(params []any) error { return errors.New("child rule") }
+
+	tests := []struct {
+		name  string
+		setup func(parent, child *Validator)
+		rule  string
+		want  string
+	}{
+		{"inherits builtins", func(parent, child *Validator) {}, "notEmpty", "required rule failed"},
+		{"inherits custom rules", func(parent, child *Validator) {
+			RegisterRule(parent, "custom", parentFailure)
+		}, "custom", "parent rule"},
+		{"sees later parent registrations", func(parent, child *Validator) {
+			RegisterRule(parent, "notEmpty", parentFailure)
+		}, "notEmpty", "parent rule"},
+		{"child rule shadows the parent's", func(parent, child *Validator) {
+			RegisterRule(parent, "custom", parentFailure)
+			RegisterRule(child, "custom", childFailure)
+		}, "custom", "child rule"},
+		{"inherits messages", func(parent, child *Validator) {
+			parent.SetMessage("notEmpty", "parent message")
+		}, "notEmpty", "parent message"},
+		{"child message shadows the parent's", func(parent, child *Validator) {
+			parent.SetMessage("notEmpty", "parent message")
+			child.SetMessage("notEmpty", "child message")
+		}, "notEmpty", "child message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := New()
+			child := parent.Child()
+			tt.setup(parent, child)
+
+			ctx := child.newContext()
+			if err := ctx.Check(tt.rule, "").Err(); err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestChildDoesNotChangeParent(t *testing.T) {
+	parent := New()
+	child := parent.Child()
+	RegisterRule(child, "custom", func(params []any) error { return errors.New("child rule") })
+	RegisterRule(child, "notEmpty", func(params []any) error { return errors.New("child rule") })
+	child.SetMessage("isEmail", "child message")
+	RegisterType(child, func(o childOrder, ctx *ValidationContext) {
+		ctx.Field("ID").Check("notEmpty", o.ID)
+	})
+
+	if parent.HasRule("custom") {
+		t.Error("the child's rule was registered on the parent")
+	}
+	ctx := parent.newContext()
+	if err := ctx.Check("notEmpty", "x").Check("isEmail", "x").Err(); err == nil || err.Error() != "must be a valid email address" {
+		t.Errorf("got %v, want the parent's own rules and messages", err)
+	}
+	if err := parent.ValidateStruct(childOrder{}); failedFields(err) != nil {
+		t.Errorf("the child's type handler ran on the parent: %v", err)
+	}
+	if err := child.ValidateStruct(childOrder{}); err == nil {
+		t.Error("the child's type handler did not run")
+	}
+}
+
+func TestWithRuleOnChild(t *testing.T) {
+	parent := New()
+	RegisterRule(parent, "custom", func(params []any) error { return errors.New("parent rule") })
+	child := parent.Child()
+
+	child.WithRule("custom", func(params []any) error { return errors.New("stub") }, func() {
+		ctx := child.newContext()
+		if err := ctx.Check("custom").Err(); err == nil || err.Error() != "stub" {
+			t.Errorf("got %v, want the stub to shadow the parent's rule", err)
+		}
+
+		ctx = parent.newContext()
+		if err := ctx.Check("custom").Err(); err == nil || err.Error() != "parent rule" {
+			t.Errorf("got %v, want the parent unaffected", err)
+		}
+	})
+
+	ctx := child.newContext()
+	if err := ctx.Check("custom").Err(); err == nil || err.Error() != "parent rule" {
+		t.Errorf("got %v, want the parent's rule back after WithRule", err)
+	}
+}
@@ -0,0 +1,135 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Classification separates failures that are safe to show to users from
+// failures caused by a misconfigured validator or a broken rule.
+type Classification int
+
+const (
+	UserError Classification = iota
+	SystemError
+)
+
+func (c Classification) String() string {
+	if c == SystemError {
+		return "system"
+	}
+
+	return "user"
+}
+
+// classifier is implemented by errors that carry their own Classification.
+type classifier interface {
+	Classification() Classification
+}
+
+type systemError struct {
+	err error
+}
+
+// SystemErrorf creates an error classified as a SystemError, for custom
+// rules that fail for reasons the user cannot fix.
+func SystemErrorf(format string, args ...any) error {
+	return &systemError{err: fmt.Errorf(format, args...)}
+}
+
+func (e *systemError) Error() string {
+	return e.err.Error()
+}
+
+func (e *systemError) Unwrap() error {
+	return e.err
+}
+
+func (e *systemError) Classification() Classification {
+	return SystemError
+}
+
+func (e *UnknownRuleError) Classification() Classification {
+	return SystemError
+}
+
+func (e *UnknownScenarioError) Classification() Classification {
+	return SystemError
+}
+
+func (e *RulePanicError) Classification() Classification {
+	return SystemError
+}
+
+// Classify reports the classification of a single failure. Failures are
+// user errors unless something in their chain says otherwise.
+func Classify(err error) Classification {
+	var c classifier
+	if errors.As(err, &c) {
+		return c.Classification()
+	}
+
+	return UserError
+}
+
+func asSystemError(err error) error {
+	if Classify(err) == SystemError {
+		return err
+	}
+
+	return &systemError{err: err}
+}
+
+// UserErrors returns the failures in err that are safe to show to users.
+func UserErrors(err error) []error {
+	var errs []error
+	for _, e := range flattenErrors(err) {
+		if Classify(e) == UserError {
+			errs = append(errs, e)
+		}
+	}
+
+	return errs
+}
+
+// HasSystemError reports whether any failure in err is a SystemError, which
+// usually means the request should fail with a 500 rather than a 422.
+func HasSystemError(err error) bool {
+	for _, e := range flattenErrors(err) {
+		if Classify(e) == SystemError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// flattenErrors expands errors joined by collect-all validation.
+func flattenErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+
+	var errs []error
+	for _, e := range joined.Unwrap() {
+		errs = append(errs, flattenErrors(e)...)
+	}
+
+	return errs
+}
+
+// internalErrorMessage replaces the details of system errors in JSON output.
+const internalErrorMessage = "internal error"
+
+// MarshalErrors renders a validation error as the JSON of a Result, with one
+// entry per failure and without offending values. System errors are reduced
+// to a generic message so internal details never reach clients.
+func MarshalErrors(err error) ([]byte, error) {
+	return json.Marshal(newResult(err, false))
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	user := &FieldError{Field: "Name", Err: &RuleError{Rule: "notEmpty", Err: errors.New("required rule failed")}}
+	system := SystemErrorf("lookup failed")
+	panicked := &RulePanicError{Rule: "custom", Value: "boom"}
+	unknown := &UnknownRuleError{Name: "noSuchRule"}
+
+	tests := []struct {
+		name          string
+		err           error
+		wantClass     Classification
+		wantUser      int
+		wantHasSystem bool
+	}{
+		{"nil", nil, UserError, 0, false},
+		{"plain error", errors.New("too short"), UserError, 1, false},
+		{"rule failure", user, UserError, 1, false},
+		{"system error", system, SystemError, 0, true},
+		{"unknown rule", unknown, SystemError, 0, true},
+		{"panic", panicked, SystemError, 0, true},
+		{"wrapped system error", fmt.Errorf("validating order: %w", system), SystemError, 0, true},
+		{"system error in a field", &FieldError{Field: "Email", Err: system}, SystemError, 0, true},
+		{"fatal user error", &FatalError{Err: user}, UserError, 1, false},
+		{"fatal system error", &FatalError{Err: panicked}, SystemError, 0, true},
+		{"joined user errors", errors.Join(user, errors.New("too short")), UserError, 2, false},
+		{"joined with a system error", errors.Join(user, system, panicked), SystemError, 1, true},
+		{"nested joins", errors.Join(errors.Join(user, system), user), SystemError, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.err); got != tt.wantClass {
+				t.Errorf("Classify() = %v, want %v", got, tt.wantClass)
+			}
+			if got := UserErrors(tt.err); len(got) != tt.wantUser {
+				t.Errorf("UserErrors() = %v, want %d errors", got, tt.wantUser)
+			}
+			if got := HasSystemError(tt.err); got != tt.wantHasSystem {
+				t.Errorf("HasSystemError() = %v, want %v", got, tt.wantHasSystem)
+			}
+		})
+	}
+}
+
+func TestClassifyFromValidation(t *testing.T) {
+	v := New(WithCollectAll())
+	RegisterRule(v, "panics", func(params []any) error { panic("boom") })
+
+	ctx := v.newContext()
+	ctx.Field("Name").Check("notEmpty", "")
+	ctx.Field("Code").Check("panics", 1)
+	ctx.Field("Kind").Check("noSuchRule")
+	err := ctx.Err()
+
+	if got := UserErrors(err); len(got) != 1 {
+		t.Errorf("UserErrors() = %v, want only the notEmpty failure", got)
+	}
+	if !HasSystemError(err) {
+		t.Error("HasSystemError() = false, want true")
+	}
+}
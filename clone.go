@@ -0,0 +1,74 @@
+package validator
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Clone returns a validator with the same configuration, rules, type
+// handlers, formats, units, enums, messages, translations, message resolver,
+// middleware, hash verifiers and grandfathered rules as v. Registrations on
+// either one do not affect the other. The built-in and composite rules of
+// the clone consult the clone, e.g. its formats and units. Grandfathering
+// counts start again from zero. A clone of a child validator inherits from
+// the same parent.
+func (v *Validator) Clone() *Validator {
+	c := newValidator(v.cfg)
+	c.parent = v.parent
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for name := range v.custom {
+		delete(c.rules, name)
+		delete(c.arities, name)
+		delete(c.ctxRules, name)
+
+		if fnc, ok := v.rules[name]; ok {
+			c.rules[name] = fnc
+		}
+		if arity, ok := v.arities[name]; ok {
+			c.arities[name] = arity
+		}
+		if fnc, ok := v.ctxRules[name]; ok {
+			c.ctxRules[name] = fnc
+		}
+		c.custom[name] = true
+	}
+
+	maps.Copy(c.typeHandlers, v.typeHandlers)
+	maps.Copy(c.scenarios, v.scenarios)
+	c.interfaces = slices.Clone(v.interfaces)
+	maps.Copy(c.formats, v.formats)
+	maps.Copy(c.units, v.units)
+	maps.Copy(c.enums, v.enums)
+	maps.Copy(c.messages, v.messages)
+	for locale, templates := range v.translations {
+		c.translations[locale] = maps.Clone(templates)
+	}
+	c.middleware = slices.Clone(v.middleware)
+	c.resolver = v.resolver
+	maps.Copy(c.hashVerifiers, v.hashVerifiers)
+
+	// composites look up their steps on the validator that runs them
+	for name, steps := range v.composites {
+		c.Compose(name, steps...)
+	}
+
+	v.grandfather.mu.Lock()
+	c.grandfather.until = make(map[string]time.Time, len(v.grandfather.until))
+	maps.Copy(c.grandfather.until, v.grandfather.until)
+	v.grandfather.mu.Unlock()
+
+	return c
+}
+
+// markCustom records that ruleName was registered or removed after the
+// built-ins, replacing any composite of that name. Callers hold v.mu.
+func (v *Validator) markCustom(ruleName string) {
+	delete(v.composites, ruleName)
+	if v.custom != nil {
+		v.custom[ruleName] = true
+	}
+}
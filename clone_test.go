@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"errors"
+	"testing"
+)
+
+type cloneOrder struct {
+	ID string
+}
+
+func TestCloneIsIndependent(t *testing.T) {
+	failing := func(params []any) error { return errors.New("custom failure") }
+
+	tests := []struct {
+		name     string
+		register func(v *Validator)
+		affected func(v *Validator) bool
+	}{
+		{"rule", func(v *Validator) {
+			RegisterRule(v, "custom", failing)
+		}, func(v *Validator) bool {
+			ctx := v.newContext()
+			err := ctx.Check("custom", "x").Err()
+			return err != nil && err.Error() == "custom failure"
+		}},
+		{"replaced builtin", func(v *Validator) {
+			RegisterRule(v, "notEmpty", failing)
+		}, func(v *Validator) bool {
+			ctx := v.newContext()
+			return ctx.Check("notEmpty", "x").Err() != nil
+		}},
+		{"message", func(v *Validator) {
+			v.SetMessage("notEmpty", "custom message")
+		}, func(v *Validator) bool {
+			ctx := v.newContext()
+			return ctx.Check("notEmpty", "").Err().Error() == "custom message"
+		}},
+		{"type handler", func(v *Validator) {
+			RegisterType(v, func(o cloneOrder, ctx *ValidationContext) {
+				ctx.Field("ID").Check("notEmpty", o.ID)
+			})
+		}, func(v *Validator) bool {
+			got := failedFields(v.ValidateStruct(cloneOrder{}))
+			return len(got) == 1 && got[0] == "ID"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on the clone", func(t *testing.T) {
+			original := New()
+			clone := original.Clone()
+			tt.register(clone)
+
+			if !tt.affected(clone) {
+				t.Error("the registration had no effect on the clone")
+			}
+			if tt.affected(original) {
+				t.Error("the registration leaked into the original")
+			}
+		})
+
+		t.Run(tt.name+" on the original", func(t *testing.T) {
+			original := New()
+			clone := original.Clone()
+			tt.register(original)
+
+			if tt.affected(clone) {
+				t.Error("the registration leaked into the clone")
+			}
+		})
+	}
+}
+
+func TestCloneCopiesRegistrations(t *testing.T) {
+	v := New()
+	RegisterRule(v, "custom", func(params []any) error { return errors.New("custom failure") })
+	v.SetMessage("notEmpty", "custom message")
+	RegisterType(v, func(o cloneOrder, ctx *ValidationContext) {
+		ctx.Field("ID").Check("notEmpty", o.ID)
+	})
+
+	clone := v.Clone()
+	ctx := clone.newContext()
+	if err := ctx.Check("custom", "x").Err(); err == nil {
+		t.Error("the clone lost the custom rule")
+	}
+	if err := clone.ValidateStruct(cloneOrder{}); err == nil || err.Error() != "ID: custom message" {
+		t.Errorf("got %v, want the handler and message to be copied", err)
+	}
+}
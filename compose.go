@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+)
+
+// RuleStep is one step of a composite rule: a registered rule and the params
+// passed to it ahead of the composite's own params.
+type RuleStep struct {
+	Rule   string
+	Params []any
+}
+
+// Compose registers ruleName as a rule that runs steps in order against its
+// params and returns the first failure as a RuleError naming the step's rule.
+// Step rules are looked up when the composite runs, so they may be registered
+// later and may be composites themselves; a composite that reaches itself is
+// reported as a system error.
+//
+//	v.Compose("strongPassword",
+//		RuleStep{Rule: "notEmpty"},
+//		RuleStep{Rule: "minLength", Params: []any{8}},
+//		RuleStep{Rule: "matches", Params: []any{`[0-9]`}},
+//	)
+func (v *Validator) Compose(ruleName string, steps ...RuleStep) {
+	steps = append([]RuleStep(nil), steps...)
+
+	RegisterRuleArity(v, ruleName, 1, func(params []any) error {
+		if cycle := v.compositeCycle(ruleName, []string{ruleName}); cycle != nil {
+			return SystemErrorf("%s: composite rule refers to itself through %s", ruleName, strings.Join(cycle, " -> "))
+		}
+
+		for _, step := range steps {
+			rule, ok := v.rule(step.Rule)
+			if !ok {
+				return &UnknownRuleError{Name: step.Rule}
+			}
+
+			var stepParams []any
+			if subjectFirstRules[step.Rule] {
+				stepParams = append(append(stepParams, params...), step.Params...)
+			} else {
+				stepParams = append(append(stepParams, step.Params...), params...)
+			}
+
+			err := rule(stepParams)
+			if err == nil {
+				continue
+			}
+
+			var re *RuleError
+			if errors.As(err, &re) || Classify(err) == SystemError {
+				return err
+			}
+
+			return &RuleError{Rule: step.Rule, Params: stepParams, Code: ruleCode(step.Rule), Err: err}
+		}
+
+		return nil
+	})
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.composites[ruleName] = steps
+}
+
+// compositeCycle returns the chain of composites leading from the last name
+// in path back into path, or nil when the steps reachable from it are
+// acyclic.
+func (v *Validator) compositeCycle(name string, path []string) []string {
+	v.mu.RLock()
+	steps := v.composites[name]
+	v.mu.RUnlock()
+
+	for _, step := range steps {
+		next := append(path[:len(path):len(path)], step.Rule)
+		for _, seen := range path {
+			if seen == step.Rule {
+				return next
+			}
+		}
+
+		if cycle := v.compositeCycle(step.Rule, next); cycle != nil {
+			return cycle
+		}
+	}
+
+	return nil
+}
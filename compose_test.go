@@ -0,0 +1,97 @@
+package validator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCompose(t *testing.T) {
+	v := New()
+	v.Compose("strongPassword",
+		RuleStep{Rule: "notEmpty"},
+		RuleStep{Rule: "minLength", Params: []any{8}},
+		RuleStep{Rule: "matches", Params: []any{`[0-9]`}},
+	)
+	// hasLetter is registered after the composite that uses it.
+	v.Compose("adminPassword",
+		RuleStep{Rule: "strongPassword"},
+		RuleStep{Rule: "hasLetter"},
+	)
+	v.Compose("hasLetter", RuleStep{Rule: "matches", Params: []any{`[a-zA-Z]`}})
+	v.Compose("selfish", RuleStep{Rule: "selfish"})
+	v.Compose("ping", RuleStep{Rule: "notEmpty"}, RuleStep{Rule: "pong"})
+	v.Compose("pong", RuleStep{Rule: "ping"})
+	v.Compose("dangling", RuleStep{Rule: "noSuchRule"})
+
+	tests := []struct {
+		name       string
+		rule       string
+		value      any
+		wantRule   string
+		wantSystem bool
+		wantMsg    string
+	}{
+		{"strong password", "strongPassword", "correct1horse", "", false, ""},
+		{"empty", "strongPassword", "", "notEmpty", false, ""},
+		{"too short", "strongPassword", "abc1", "minLength", false, ""},
+		{"no digit", "strongPassword", "correcthorse", "matches", false, ""},
+		{"nested composite passes", "adminPassword", "correct1horse", "", false, ""},
+		{"nested composite keeps the inner rule", "adminPassword", "short1", "minLength", false, ""},
+		{"lazily resolved step", "adminPassword", "12345678", "matches", false, ""},
+		{"self reference", "selfish", "x", "", true, "selfish -> selfish"},
+		{"indirect cycle", "ping", "x", "", true, "ping -> pong -> ping"},
+		{"unknown step", "dangling", "x", "", true, "noSuchRule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check(tt.rule, tt.value).Err()
+			if tt.wantRule == "" && !tt.wantSystem {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if tt.wantSystem {
+				if Classify(err) != SystemError {
+					t.Errorf("%v classified as %v, want a system error", err, Classify(err))
+				}
+				if !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Errorf("got %q, want it to mention %q", err, tt.wantMsg)
+				}
+				return
+			}
+
+			var re *RuleError
+			if !errors.As(err, &re) || re.Rule != tt.wantRule {
+				t.Errorf("got %v (%#v), want a RuleError for %s", err, re, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestComposeInTags(t *testing.T) {
+	v := New()
+	v.Compose("strongPassword",
+		RuleStep{Rule: "notEmpty"},
+		RuleStep{Rule: "minLength", Params: []any{8}},
+	)
+
+	type signup struct {
+		Password string `validate:"strongPassword"`
+	}
+
+	if err := v.ValidateStruct(signup{Password: "correct horse"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	err := v.ValidateStruct(signup{Password: "short"})
+	if got := failedFields(err); len(got) != 1 || got[0] != "Password" {
+		t.Errorf("failed fields = %v, want [Password]", got)
+	}
+}
@@ -0,0 +1,98 @@
+package validator
+
+import "reflect"
+
+// When skips the checks that follow it, including nested Validate calls,
+// unless cond is true. The block lasts until the matching End. Blocks nest:
+// a check inside nested blocks only runs if every condition holds.
+func (ctx *ValidationContext) When(cond bool) *ValidationContext {
+	ctx.conditions = append(ctx.conditions, cond)
+
+	return ctx
+}
+
+// Unless is When with the condition inverted.
+func (ctx *ValidationContext) Unless(cond bool) *ValidationContext {
+	return ctx.When(!cond)
+}
+
+// WhenFn is When with a condition that is only evaluated while validation is
+// still passing. Once a failure has been recorded the block is skipped;
+// groups still running are not waited for.
+func (ctx *ValidationContext) WhenFn(cond func() bool) *ValidationContext {
+	if ctx.skip() || ctx.failed() {
+		return ctx.When(false)
+	}
+
+	return ctx.When(cond())
+}
+
+// End closes the innermost When or Unless block.
+func (ctx *ValidationContext) End() *ValidationContext {
+	if len(ctx.conditions) > 0 {
+		ctx.conditions = ctx.conditions[:len(ctx.conditions)-1]
+	}
+
+	return ctx
+}
+
+// Optional skips the checks that follow it, until the next call to Field,
+// when value is absent: the zero value of its type, such as an empty string,
+// a nil pointer or 0. A pointer to a zero value counts as present.
+func (ctx *ValidationContext) Optional(value any) *ValidationContext {
+	ctx.absent = isAbsent(reflect.ValueOf(value))
+
+	return ctx
+}
+
+// OptionalNil is Optional for values where only nil counts as absent: a nil
+// pointer, map, slice or interface, or a typed nil stored in an interface.
+// Zero values such as "" and 0 are still checked.
+func (ctx *ValidationContext) OptionalNil(value any) *ValidationContext {
+	ctx.absent = isNil(reflect.ValueOf(value))
+
+	return ctx
+}
+
+func isAbsent(rv reflect.Value) bool {
+	return !rv.IsValid() || rv.IsZero()
+}
+
+func isNil(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return true
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// inactive reports whether a When block or Optional is skipping checks.
+func (ctx *ValidationContext) inactive() bool {
+	if ctx.absent {
+		return true
+	}
+
+	for _, cond := range ctx.conditions {
+		if !cond {
+			return true
+		}
+	}
+
+	return false
+}
+
+// skipped is skip for the start of a check. A skipped check did not fail, so
+// it also makes a following Message a no-op.
+func (ctx *ValidationContext) skipped() bool {
+	if ctx.skip() {
+		ctx.lastFailed = false
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"slices"
+	"testing"
+)
+
+type optionalContact struct {
+	Email *string
+	Age   *int
+	Extra any
+	Name  string
+}
+
+func TestOptional(t *testing.T) {
+	v := New(WithCollectAll())
+	RegisterType(v, func(c optionalContact, ctx *ValidationContext) {
+		ctx.Field("Email").Optional(c.Email).Check("isEmail", c.Email)
+		ctx.Field("Age").Optional(c.Age).Check("greaterThan", 17, c.Age)
+		ctx.Field("Extra").Optional(c.Extra).Check("notEmpty", c.Extra)
+		ctx.Field("Name").Check("notEmpty", c.Name)
+	})
+
+	tests := []struct {
+		name  string
+		value optionalContact
+		want  []string
+	}{
+		{"all absent", optionalContact{Name: "ada"}, nil},
+		{"nil pointer skips", optionalContact{Email: nil, Name: "ada"}, nil},
+		{"pointer is dereferenced", optionalContact{Email: ptrTo("ada@example.com"), Age: ptrTo(36), Name: "ada"}, nil},
+		{"invalid pointee", optionalContact{Email: ptrTo("ada"), Age: ptrTo(12), Name: "ada"}, []string{"Email", "Age"}},
+		{"pointer to empty string is present", optionalContact{Email: ptrTo(""), Name: "ada"}, []string{"Email"}},
+		{"pointer to zero is present", optionalContact{Age: ptrTo(0), Name: "ada"}, []string{"Age"}},
+		{"typed nil in interface skips", optionalContact{Extra: (*string)(nil), Name: "ada"}, nil},
+		{"zero value in interface skips", optionalContact{Extra: "", Name: "ada"}, nil},
+		{"skipping ends at the next field", optionalContact{}, []string{"Name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateStruct(tt.value)
+			if got := failedFields(err); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v (%v)", got, tt.want, err)
+			}
+			if err != nil && Classify(err) != UserError {
+				t.Errorf("%v classified as %v, want a user error", err, Classify(err))
+			}
+		})
+	}
+}
+
+func TestWhen(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(ctx *ValidationContext)
+		want  []string
+	}{
+		{"applied", func(ctx *ValidationContext) {
+			ctx.When(true).Field("Company").Check("notEmpty", "").End()
+		}, []string{"Company"}},
+		{"skipped", func(ctx *ValidationContext) {
+			ctx.When(false).Field("Company").Check("notEmpty", "").End()
+		}, nil},
+		{"unless applied", func(ctx *ValidationContext) {
+			ctx.Unless(false).Field("Company").Check("notEmpty", "").End()
+		}, []string{"Company"}},
+		{"unless skipped", func(ctx *ValidationContext) {
+			ctx.Unless(true).Field("Company").Check("notEmpty", "").End()
+		}, nil},
+		{"skips Must and Validate", func(ctx *ValidationContext) {
+			ctx.When(false).
+				Field("Terms").Must(func() bool { return false }).
+				Field("City").Validate(sharedCity{}).
+				End()
+		}, nil},
+		{"nested, both hold", func(ctx *ValidationContext) {
+			ctx.When(true).When(true).Field("VAT").Check("notEmpty", "").End().End()
+		}, []string{"VAT"}},
+		{"nested, outer fails", func(ctx *ValidationContext) {
+			ctx.When(false).When(true).Field("VAT").Check("notEmpty", "").End().End()
+		}, nil},
+		{"nested, inner fails", func(ctx *ValidationContext) {
+			ctx.When(true).
+				When(false).Field("VAT").Check("notEmpty", "").End().
+				Field("Company").Check("notEmpty", "").
+				End()
+		}, []string{"Company"}},
+		{"End restores checking", func(ctx *ValidationContext) {
+			ctx.When(false).Field("Company").Check("notEmpty", "").End()
+			ctx.Field("Name").Check("notEmpty", "")
+		}, []string{"Name"}},
+		{"End restores the outer block", func(ctx *ValidationContext) {
+			ctx.When(false).
+				When(true).End().
+				Field("Company").Check("notEmpty", "").
+				End()
+		}, nil},
+		{"extra End is harmless", func(ctx *ValidationContext) {
+			ctx.End().End().Field("Name").Check("notEmpty", "")
+		}, []string{"Name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New(WithCollectAll()).newContext()
+			tt.check(&ctx)
+
+			if got := failedFields(ctx.Err()); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+			if len(ctx.conditions) != 0 {
+				t.Errorf("%d blocks left open", len(ctx.conditions))
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Config is the configuration of a Validator. The With options fill in a
+// Config for New; NewFromConfig takes one directly and checks it first.
+type Config struct {
+	// CollectAll makes contexts accumulate failures, see WithCollectAll.
+	CollectAll bool
+	// Strict makes configuration mistakes panic, see WithStrict.
+	Strict bool
+	// WithoutBuiltins leaves out the built-in rules.
+	WithoutBuiltins bool
+	// Rules are registered after, and so replace, the built-ins.
+	Rules map[string]RuleFunc
+	// Logger receives usage errors. Nil discards them.
+	Logger Logger
+	// PropagatePanics lets panics from rules reach the caller.
+	PropagatePanics bool
+	// Clock is used for grandfathering deadlines. Nil means time.Now.
+	Clock func() time.Time
+	// Capture is called for every failed validation, see WithCapture.
+	Capture func(Capture)
+	// CaptureExcerpts is the number of runes kept from each end of failing
+	// values in captures. It requires Capture.
+	CaptureExcerpts int
+	// RedactValues leaves offending values out of every Result.
+	RedactValues bool
+	// Translations are message templates by locale and then rule name, as
+	// added with AddTranslation.
+	Translations map[string]map[string]string
+	// DefaultLocale is the fallback for translated messages. NewFromConfig
+	// requires it to be one of the locales in Translations.
+	DefaultLocale string
+	// UseJSONTagNames reports struct fields by their json names, see
+	// WithJSONTagNames.
+	UseJSONTagNames bool
+	// ConcurrentFields validates struct fields in parallel, see
+	// WithConcurrentFields.
+	ConcurrentFields bool
+	// FailFastGroups stops the other groups of a context once one fails,
+	// see WithFailFastGroups.
+	FailFastGroups bool
+}
+
+// NewFromConfig creates a validator from cfg, returning every problem with
+// cfg at once instead of a validator that misbehaves later.
+func NewFromConfig(cfg Config) (*Validator, error) {
+	if err := Validate(configValidator(), cfg); err != nil {
+		return nil, err
+	}
+
+	return newValidator(cfg), nil
+}
+
+// configValidator checks Config with the package itself.
+var configValidator = sync.OnceValue(func() *Validator {
+	v := New(WithCollectAll())
+	RegisterType(v, func(cfg Config, ctx *ValidationContext) {
+		ctx.Field("CaptureExcerpts").
+			Check("greaterOrEqual", 0, cfg.CaptureExcerpts).
+			Message("CaptureExcerpts must not be negative").
+			MustMessage("CaptureExcerpts requires Capture", func() bool {
+				return cfg.CaptureExcerpts <= 0 || cfg.Capture != nil
+			})
+
+		ctx.Field("DefaultLocale").
+			MustMessage("DefaultLocale "+cfg.DefaultLocale+" has no translations", func() bool {
+				_, ok := cfg.Translations[cfg.DefaultLocale]
+				return cfg.DefaultLocale == "" || ok
+			})
+
+		names := make([]string, 0, len(cfg.Rules))
+		for name := range cfg.Rules {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			ctx.Field("Rules["+name+"]").
+				MustMessage("rule name must not be empty", func() bool { return name != "" }).
+				MustMessage("rule "+name+" is nil", func() bool { return cfg.Rules[name] != nil })
+		}
+	})
+
+	return v
+})
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNewFromConfig(t *testing.T) {
+	even := func(params []any) error {
+		if params[0].(int)%2 != 0 {
+			return errors.New("is odd")
+		}
+		return nil
+	}
+	fr := map[string]map[string]string{"fr": {"even": "doit être pair"}}
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantFields []string
+	}{
+		{"zero config", Config{}, nil},
+		{"full config", Config{
+			CollectAll:      true,
+			Rules:           map[string]RuleFunc{"even": even},
+			Capture:         func(Capture) {},
+			CaptureExcerpts: 4,
+			Translations:    fr,
+			DefaultLocale:   "fr",
+		}, nil},
+		{"negative excerpts", Config{Capture: func(Capture) {}, CaptureExcerpts: -1}, []string{"CaptureExcerpts"}},
+		{"excerpts without capture", Config{CaptureExcerpts: 4}, []string{"CaptureExcerpts"}},
+		{"nil rule", Config{Rules: map[string]RuleFunc{"even": nil}}, []string{"Rules[even]"}},
+		{"empty rule name", Config{Rules: map[string]RuleFunc{"": even}}, []string{"Rules[]"}},
+		{"unknown default locale", Config{DefaultLocale: "de"}, []string{"DefaultLocale"}},
+		{"default locale not translated", Config{Translations: fr, DefaultLocale: "de"}, []string{"DefaultLocale"}},
+		{"every problem at once", Config{
+			CaptureExcerpts: 4,
+			Rules:           map[string]RuleFunc{"even": nil},
+			DefaultLocale:   "de",
+		}, []string{"CaptureExcerpts", "DefaultLocale", "Rules[even]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewFromConfig(tt.cfg)
+			if got := failedFields(err); !slices.Equal(got, tt.wantFields) {
+				t.Fatalf("failed fields = %v, want %v (err %v)", got, tt.wantFields, err)
+			}
+			if (v == nil) == (tt.wantFields == nil) {
+				t.Errorf("validator = %v with err %v", v, err)
+			}
+		})
+	}
+}
+
+func TestNewFromConfigApplies(t *testing.T) {
+	v, err := NewFromConfig(Config{
+		Rules: map[string]RuleFunc{"even": func(params []any) error {
+			if params[0].(int)%2 != 0 {
+				return errors.New("is odd")
+			}
+			return nil
+		}},
+		Translations:  map[string]map[string]string{"fr": {"even": "doit être pair"}},
+		DefaultLocale: "fr",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := v.newContext()
+	if err := ctx.Check("even", 3).Err(); err == nil || err.Error() != "doit être pair" {
+		t.Errorf("got %v, want the default locale's translation", err)
+	}
+}
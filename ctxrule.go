@@ -0,0 +1,44 @@
+package validator
+
+import "context"
+
+// RuleFuncCtx is a rule that needs a context.Context, for checks that do
+// I/O and must honor deadlines and cancellation.
+type RuleFuncCtx func(ctx context.Context, params []any) error
+
+// RegisterRuleCtx registers a rule that receives the context passed to
+// CheckCtx or ValidateCtx. The other entry points call it with
+// context.Background. Failures caused by cancellation or an expired deadline
+// are system errors.
+func RegisterRuleCtx(v *Validator, ruleName string, fnc RuleFuncCtx) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.rules[ruleName] = func(params []any) error {
+		return fnc(context.Background(), params)
+	}
+	delete(v.arities, ruleName)
+	v.ctxRules[ruleName] = fnc
+	v.markCustom(ruleName)
+}
+
+// CheckCtx is Check with a context.Context for rules registered with
+// RegisterRuleCtx. Other rules ignore it.
+func (ctx *ValidationContext) CheckCtx(goctx context.Context, ruleName string, params ...any) *ValidationContext {
+	return ctx.check(goctx, ruleName, params, false)
+}
+
+// ValidateCtx validates value like ValidateStruct, passing goctx to the
+// rules that take one. It is short for ValidateStruct with WithContext.
+func (v *Validator) ValidateCtx(goctx context.Context, value any) error {
+	return v.ValidateStruct(value, WithContext(goctx))
+}
+
+// context returns the context given with WithContext, or context.Background.
+func (ctx *ValidationContext) context() context.Context {
+	if ctx.goctx == nil {
+		return context.Background()
+	}
+
+	return ctx.goctx
+}
@@ -0,0 +1,89 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type ctxHost struct {
+	Name string `validate:"reachable"`
+}
+
+func TestRegisterRuleCtx(t *testing.T) {
+	v := New()
+	RegisterRuleCtx(v, "reachable", func(goctx context.Context, params []any) error {
+		select {
+		case <-time.After(time.Second):
+			return nil
+		case <-goctx.Done():
+			return goctx.Err()
+		}
+	})
+	RegisterRuleCtx(v, "deadlineSet", func(goctx context.Context, params []any) error {
+		if _, ok := goctx.Deadline(); !ok {
+			return errors.New("no deadline")
+		}
+		return nil
+	})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		run      func() error
+		wantIs   error
+		wantUser bool
+	}{
+		{"ValidateCtx with a cancelled context", func() error {
+			return v.ValidateCtx(cancelled, ctxHost{Name: "example.com"})
+		}, context.Canceled, false},
+		{"ValidateCtx with an expiring deadline", func() error {
+			goctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			return v.ValidateCtx(goctx, ctxHost{Name: "example.com"})
+		}, context.DeadlineExceeded, false},
+		{"CheckCtx passes the context", func() error {
+			goctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			ctx := v.newContext()
+			return ctx.CheckCtx(goctx, "deadlineSet").Err()
+		}, nil, false},
+		{"Check uses context.Background", func() error {
+			ctx := v.newContext()
+			return ctx.Check("deadlineSet").Err()
+		}, nil, true},
+		{"CheckCtx ignores the context for other rules", func() error {
+			ctx := v.newContext()
+			return ctx.CheckCtx(context.Background(), "notEmpty", "x").Err()
+		}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.run()
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("took %v, want the rule to stop on cancellation", elapsed)
+			}
+
+			switch {
+			case tt.wantIs != nil:
+				if !errors.Is(err, tt.wantIs) {
+					t.Fatalf("got %v, want %v", err, tt.wantIs)
+				}
+				if Classify(err) != SystemError {
+					t.Errorf("%v classified as %v, want a system error", err, Classify(err))
+				}
+			case tt.wantUser:
+				if err == nil || Classify(err) != UserError {
+					t.Fatalf("got %v, want a user error", err)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
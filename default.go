@@ -0,0 +1,42 @@
+package validator
+
+import "sync"
+
+var defaultValidator = sync.OnceValue(func() *Validator {
+	return New()
+})
+
+// Default returns the package-level validator used by Check and
+// RegisterDefaultRule. It is created with New on first use, so it has the
+// built-in rules.
+func Default() *Validator {
+	return defaultValidator()
+}
+
+// Check runs a rule against the default validator on a fresh context, e.g.
+//
+//	err := validator.Check("isEmail", email).Err()
+func Check(ruleName string, params ...any) *ValidationContext {
+	ctx := Default().newContext()
+	return ctx.Check(ruleName, params...)
+}
+
+// RegisterDefaultRule registers a rule on the default validator. The
+// package-level RegisterRule takes the validator to register on, so the
+// default validator has its own entry point.
+func RegisterDefaultRule(ruleName string, fnc RuleFunc) {
+	RegisterRule(Default(), ruleName, fnc)
+}
+
+// RegisterTypeDefault registers a type handler on the default validator. Like
+// RegisterDefaultRule, it is safe to call from init functions in any package.
+func RegisterTypeDefault[T any](handler func(s T, ctx *ValidationContext)) {
+	RegisterType(Default(), handler)
+}
+
+// ValidateDefault validates value with the default validator, like
+// ValidateStruct. The generic Validate takes the validator to use, so the
+// default validator has its own entry point.
+func ValidateDefault(value any) error {
+	return Default().ValidateStruct(value)
+}
@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+type defaultOrder struct {
+	ID    string
+	Total int
+}
+
+func init() {
+	RegisterDefaultRule("defaultTestEven", func(params []any) error {
+		n, ok := params[0].(int)
+		if !ok {
+			return SystemErrorf("defaultTestEven: unsupported type %T", params[0])
+		}
+		if n%2 != 0 {
+			return errors.New("is not even")
+		}
+		return nil
+	})
+	RegisterTypeDefault(func(o defaultOrder, ctx *ValidationContext) {
+		ctx.Field("ID").Check("notEmpty", o.ID)
+		ctx.Field("Total").Check("defaultTestEven", o.Total)
+	})
+}
+
+func TestDefault(t *testing.T) {
+	if Default() != Default() {
+		t.Fatal("Default() returned different validators")
+	}
+
+	tests := []struct {
+		name      string
+		run       func() error
+		wantErr   bool
+		wantField string
+	}{
+		{"Check with a built-in", func() error { return Check("notEmpty", "x").Err() }, false, ""},
+		{"Check failing", func() error { return Check("notEmpty", "").Err() }, true, ""},
+		{"RegisterDefaultRule", func() error { return Check("defaultTestEven", 4).Err() }, false, ""},
+		{"RegisterDefaultRule failing", func() error { return Check("defaultTestEven", 3).Err() }, true, ""},
+		{"ValidateDefault", func() error {
+			return ValidateDefault(defaultOrder{ID: "a1", Total: 10})
+		}, false, ""},
+		{"ValidateDefault failing", func() error {
+			return ValidateDefault(defaultOrder{Total: 10})
+		}, true, "ID"},
+		{"RegisterTypeDefault through Validate", func() error {
+			return Validate(Default(), defaultOrder{ID: "a1", Total: 3})
+		}, true, "Total"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantField == "" {
+				return
+			}
+			if got := failedFields(err); len(got) != 1 || got[0] != tt.wantField {
+				t.Errorf("failed fields = %v, want [%s]", got, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestDefaultConcurrentUse(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if i%2 == 0 {
+				RegisterDefaultRule("defaultTestConcurrent", func(params []any) error { return nil })
+			}
+			if err := Check("notEmpty", "x").Err(); err != nil {
+				t.Errorf("Check: %v", err)
+			}
+			if err := ValidateDefault(defaultOrder{ID: "a", Total: 2}); err != nil {
+				t.Errorf("ValidateDefault: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,124 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RegisterEnum declares the members of the enum called name, for rules such
+// as coversEnum. Members may be strings, integers or named types of either.
+func RegisterEnum(v *Validator, name string, members ...any) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.enums[name] = append([]any(nil), members...)
+}
+
+func (v *Validator) enum(name string) ([]any, bool) {
+	v.mu.RLock()
+	members, ok := v.enums[name]
+	v.mu.RUnlock()
+
+	if !ok && v.parent != nil {
+		return v.parent.enum(name)
+	}
+
+	return members, ok
+}
+
+// enumKey normalizes a member or map key so that a named type compares equal
+// to its underlying value.
+func enumKey(value any) any {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	default:
+		return value
+	}
+}
+
+func registerEnums(validator *Validator) {
+	// coversEnum takes (map, enumName, options...). It checks that the map has
+	// a key for every member of the enum and no other keys. The option
+	// "allowExtra" permits other keys, and "subset" followed by members
+	// requires only those members.
+	RegisterRuleArity(validator, "coversEnum", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("coversEnum: expected at least 2 parameters, got %d", len(params))
+		}
+
+		m := reflect.ValueOf(params[0])
+		if m.Kind() != reflect.Map {
+			return SystemErrorf("coversEnum: unsupported type %T, expected a map", params[0])
+		}
+
+		name, ok := params[1].(string)
+		if !ok {
+			return SystemErrorf("coversEnum: unsupported type %T for enum name", params[1])
+		}
+
+		members, ok := validator.enum(name)
+		if !ok {
+			return SystemErrorf("coversEnum: enum %q has not been registered", name)
+		}
+
+		required := members
+		allowExtra := false
+		for i := 2; i < len(params); i++ {
+			switch params[i] {
+			case "allowExtra":
+				allowExtra = true
+			case "subset":
+				required = params[i+1:]
+				i = len(params)
+			default:
+				return SystemErrorf("coversEnum: unknown option %v", params[i])
+			}
+		}
+
+		declared := make(map[any]bool, len(members))
+		for _, member := range members {
+			declared[enumKey(member)] = true
+		}
+
+		keys := make(map[any]bool, m.Len())
+		var unexpected []string
+		for _, key := range sortedKeys(m) {
+			k := enumKey(key.Interface())
+			keys[k] = true
+			if !declared[k] && !allowExtra {
+				unexpected = append(unexpected, fmt.Sprint(key))
+			}
+		}
+
+		var missing []string
+		for _, member := range required {
+			if !declared[enumKey(member)] {
+				return SystemErrorf("coversEnum: %v is not a member of %s", member, name)
+			}
+
+			if !keys[enumKey(member)] {
+				missing = append(missing, fmt.Sprint(member))
+			}
+		}
+
+		var problems []string
+		if len(missing) > 0 {
+			problems = append(problems, fmt.Sprintf("missing %s keys [%s]", name, strings.Join(missing, ", ")))
+		}
+		if len(unexpected) > 0 {
+			problems = append(problems, fmt.Sprintf("unexpected keys [%s]", strings.Join(unexpected, ", ")))
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("coversEnum: %s", strings.Join(problems, "; "))
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,73 @@
+//go:build js && wasm
+
+// Command wasm exposes the validator to the browser so the frontend runs the
+// same rules as the server. Build with:
+//
+//	GOOS=js GOARCH=wasm go build -o validator.wasm ./examples/wasm
+package main
+
+import (
+	"encoding/json"
+	"syscall/js"
+
+	validator "github.com/CColeson/NoBSGoValidator"
+)
+
+type User struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Age   int    `json:"age"`
+}
+
+var v = validator.New()
+
+var decoders = map[string]func(data []byte) (any, error){
+	"User": decode[User],
+}
+
+func decode[T any](data []byte) (any, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+func validateJSON(typeName string, jsonString string) string {
+	errs := []string{}
+
+	decoder, ok := decoders[typeName]
+	if !ok {
+		errs = append(errs, "unknown type "+typeName)
+	} else if value, err := decoder([]byte(jsonString)); err != nil {
+		errs = append(errs, err.Error())
+	} else if err := validator.Validate(v, value); err != nil {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, e := range joined.Unwrap() {
+				errs = append(errs, e.Error())
+			}
+		} else {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	out, _ := json.Marshal(errs)
+	return string(out)
+}
+
+func main() {
+	validator.RegisterType(v, func(u User, ctx *validator.ValidationContext) {
+		ctx.CollectAll().
+			Check("notEmpty", u.Name).Message("name is required").
+			Check("isEmail", u.Email).
+			Check("greaterThan", 17, u.Age).Message("must be an adult")
+	})
+
+	js.Global().Set("validateJSON", js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 2 {
+			return `["validateJSON expects (typeName, jsonString)"]`
+		}
+
+		return validateJSON(args[0].String(), args[1].String())
+	}))
+
+	select {}
+}
@@ -0,0 +1,244 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// RegisterFormat makes ruleName available to the format rule under name, so
+// `validate:"format=name"` dispatches to ruleName.
+func RegisterFormat(v *Validator, name string, ruleName string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.formats[name] = ruleName
+}
+
+func (v *Validator) format(name string) (RuleFunc, bool) {
+	for w := v; w != nil; w = w.parent {
+		w.mu.RLock()
+		ruleName, ok := w.formats[name]
+		w.mu.RUnlock()
+
+		if ok {
+			return v.rule(ruleName)
+		}
+	}
+
+	return nil, false
+}
+
+func registerFormats(validator *Validator) {
+	RegisterRuleArity(validator, "format", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("format: expected 2 parameters, got %d", len(params))
+		}
+
+		name, ok := params[0].(string)
+		if !ok {
+			return SystemErrorf("format: unsupported type %T for format name", params[0])
+		}
+
+		fnc, ok := validator.format(name)
+		if !ok {
+			return SystemErrorf("format: %q has not been registered", name)
+		}
+
+		if err := fnc(params[1:]); err != nil {
+			return fmt.Errorf("format %s: %w", name, err)
+		}
+
+		return nil
+	})
+
+	// isUUID optionally takes a leading hyphens mode: "hyphens" (the
+	// default) for the 8-4-4-4-12 form, "noHyphens" for 32 hex digits or
+	// "anyHyphens" for either.
+	RegisterRuleArity(validator, "isUUID", 1, func(params []any) error {
+		mode := "hyphens"
+		if len(params) > 1 {
+			if m, ok := params[0].(string); ok && uuidModes[m] {
+				mode, params = m, params[1:]
+			}
+		}
+
+		for _, p := range params {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("isUUID: unsupported type %T", p)
+			}
+
+			if !isUUID(str, mode) {
+				return fmt.Errorf("isUUID: %q is not a valid UUID", str)
+			}
+		}
+
+		return nil
+	})
+
+	// isURL optionally takes the allowed schemes first, each as a
+	// "scheme=name" parameter, e.g. `validate:"isURL=scheme=ftp:scheme=sftp"`.
+	// http and https are allowed when none are given.
+	RegisterRuleArity(validator, "isURL", 1, func(params []any) error {
+		schemes, params := urlSchemes(params)
+		if len(params) == 0 {
+			return SystemErrorf("isURL: expected a URL after the schemes")
+		}
+
+		for _, p := range params {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("isURL: unsupported type %T", p)
+			}
+
+			u, err := url.Parse(str)
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("isURL: %q is not an absolute URL", str)
+			}
+
+			if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+				return fmt.Errorf("isURL: scheme of %q is not one of %v", str, schemes)
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "alphanumeric", 1, func(params []any) error {
+		return checkASCII("alphanumeric", "letters and digits", params, func(c byte) bool {
+			return isDigit(c) || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+		})
+	})
+
+	RegisterRuleArity(validator, "numericString", 1, func(params []any) error {
+		return checkASCII("numericString", "digits", params, isDigit)
+	})
+
+	// isNumeric, isAlpha and isAlphanumeric are the Unicode counterparts of
+	// numericString and alphanumeric, so "٣" is numeric and "é" is alpha.
+	// Like them, they reject empty strings.
+	RegisterRuleArity(validator, "isNumeric", 1, func(params []any) error {
+		return checkRunes("isNumeric", "digits", params, unicode.IsDigit)
+	})
+
+	RegisterRuleArity(validator, "isAlpha", 1, func(params []any) error {
+		return checkRunes("isAlpha", "letters", params, unicode.IsLetter)
+	})
+
+	RegisterRuleArity(validator, "isAlphanumeric", 1, func(params []any) error {
+		return checkRunes("isAlphanumeric", "letters and digits", params, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		})
+	})
+
+	RegisterFormat(validator, "email", "isEmail")
+	RegisterFormat(validator, "uuid", "isUUID")
+	RegisterFormat(validator, "url", "isURL")
+	RegisterFormat(validator, "alphanumeric", "alphanumeric")
+	RegisterFormat(validator, "numeric", "numericString")
+}
+
+var uuidModes = map[string]bool{"hyphens": true, "noHyphens": true, "anyHyphens": true}
+
+// isUUID reports whether s is a version 1 to 7 UUID of the RFC 9562 variant,
+// in either case, written as mode allows.
+func isUUID(s string, mode string) bool {
+	switch {
+	case len(s) == 36 && mode != "noHyphens":
+		for i := 0; i < len(s); i++ {
+			if (s[i] == '-') != (i == 8 || i == 13 || i == 18 || i == 23) {
+				return false
+			}
+		}
+		s = strings.ReplaceAll(s, "-", "")
+	case len(s) == 32 && mode != "hyphens":
+	default:
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if !isHexDigit(s[i]) {
+			return false
+		}
+	}
+
+	return '1' <= s[12] && s[12] <= '7' && strings.ContainsRune("89abAB", rune(s[16]))
+}
+
+// urlSchemes splits the leading "scheme=name" params of isURL from the URLs
+// after them, defaulting to http and https.
+func urlSchemes(params []any) ([]string, []any) {
+	var schemes []string
+	for len(params) > 0 {
+		str, _ := params[0].(string)
+		scheme, ok := strings.CutPrefix(str, "scheme=")
+		if !ok {
+			break
+		}
+
+		schemes = append(schemes, strings.ToLower(scheme))
+		params = params[1:]
+	}
+
+	if schemes == nil {
+		schemes = []string{"http", "https"}
+	}
+
+	return schemes, params
+}
+
+// checkASCII requires every param to be a non-empty string of bytes accepted
+// by ok, described as what in errors.
+func checkASCII(ruleName string, what string, params []any, ok func(c byte) bool) error {
+	for _, p := range params {
+		str, isString := p.(string)
+		if !isString {
+			return SystemErrorf("%s: unsupported type %T", ruleName, p)
+		}
+
+		if str == "" {
+			return fmt.Errorf("%s: must not be empty", ruleName)
+		}
+
+		for i := 0; i < len(str); i++ {
+			if !ok(str[i]) {
+				return fmt.Errorf("%s: %q must contain only %s", ruleName, str, what)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkRunes is checkASCII for rules that accept any Unicode rune ok allows.
+func checkRunes(ruleName string, what string, params []any, ok func(r rune) bool) error {
+	for _, p := range params {
+		str, isString := p.(string)
+		if !isString {
+			return SystemErrorf("%s: unsupported type %T", ruleName, p)
+		}
+
+		if str == "" {
+			return fmt.Errorf("%s: must not be empty", ruleName)
+		}
+
+		for _, r := range str {
+			if !ok(r) {
+				return fmt.Errorf("%s: %q must contain only %s", ruleName, str, what)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
@@ -0,0 +1,198 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"https", []any{"https://example.com/a?b=c"}, false, UserError},
+		{"http uppercase scheme", []any{"HTTP://example.com"}, false, UserError},
+		{"ftp not allowed by default", []any{"ftp://example.com"}, true, UserError},
+		{"relative", []any{"/path"}, true, UserError},
+		{"no host", []any{"https://"}, true, UserError},
+		{"explicit scheme", []any{"scheme=ftp", "ftp://example.com"}, false, UserError},
+		{"explicit schemes", []any{"scheme=ftp", "scheme=SFTP", "sftp://example.com"}, false, UserError},
+		{"explicit scheme replaces default", []any{"scheme=ftp", "https://example.com"}, true, UserError},
+		{"explicit scheme, not a URL", []any{"scheme=ftp", "example.com"}, true, UserError},
+		{"schemes without URL", []any{"scheme=ftp"}, true, SystemError},
+		{"non-string", []any{42}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("isURL", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isURL(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("isURL(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+type urlTagged struct {
+	Mirror string `validate:"isURL=scheme=ftp:scheme=sftp"`
+}
+
+func TestIsURLTag(t *testing.T) {
+	tests := []struct {
+		mirror  string
+		wantErr bool
+	}{
+		{"ftp://example.com", false},
+		{"sftp://example.com", false},
+		{"https://example.com", true},
+		{"example.com", true},
+	}
+
+	for _, tt := range tests {
+		err := New().ValidateStruct(urlTagged{Mirror: tt.mirror})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Mirror %q: got %v, wantErr %v", tt.mirror, err, tt.wantErr)
+		}
+	}
+}
+
+type formatTagged struct {
+	Email string `validate:"format=email"`
+	ID    string `validate:"format=uuid"`
+}
+
+func TestFormat(t *testing.T) {
+	v := New()
+	RegisterRule(v, "isHexColor", func(params []any) error {
+		for _, p := range params {
+			if s, _ := p.(string); len(s) != 7 || s[0] != '#' {
+				return fmt.Errorf("%v is not a hex color", p)
+			}
+		}
+		return nil
+	})
+	RegisterFormat(v, "color", "isHexColor")
+
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"email", []any{"email", "ada@example.com"}, false, UserError},
+		{"bad email", []any{"email", "ada"}, true, UserError},
+		{"uuid", []any{"uuid", "123e4567-e89b-12d3-a456-426614174000"}, false, UserError},
+		{"bad uuid", []any{"uuid", "123e4567"}, true, UserError},
+		{"custom format", []any{"color", "#ff00aa"}, false, UserError},
+		{"bad custom format", []any{"color", "red"}, true, UserError},
+		{"unknown format", []any{"ipv9", "x"}, true, SystemError},
+		{"format name not a string", []any{7, "x"}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check("format", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("format(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("format(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+
+	t.Run("tags", func(t *testing.T) {
+		got := failedFields(New(WithCollectAll()).ValidateStruct(formatTagged{Email: "ada", ID: "x"}))
+		if !slices.Equal(got, []string{"Email", "ID"}) {
+			t.Errorf("failed fields = %v, want [Email ID]", got)
+		}
+	})
+}
+
+func TestIsUUID(t *testing.T) {
+	const (
+		v4       = "123e4567-e89b-42d3-a456-426614174000"
+		v4Bare   = "123e4567e89b42d3a456426614174000"
+		v7       = "01890a5d-ac96-774b-bcce-b302099a8057"
+		upper    = "123E4567-E89B-42D3-A456-426614174000"
+		v8       = "123e4567-e89b-82d3-a456-426614174000"
+		nilUUID  = "00000000-0000-0000-0000-000000000000"
+		badVar   = "123e4567-e89b-42d3-c456-426614174000"
+		misplace = "123e4567e-89b-42d3-a456-426614174000"
+	)
+
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"v4", []any{v4}, false, UserError},
+		{"v7", []any{v7}, false, UserError},
+		{"uppercase", []any{upper}, false, UserError},
+		{"version 8", []any{v8}, true, UserError},
+		{"nil uuid", []any{nilUUID}, true, UserError},
+		{"wrong variant", []any{badVar}, true, UserError},
+		{"misplaced hyphen", []any{misplace}, true, UserError},
+		{"not hex", []any{"123e4567-e89b-42d3-a456-42661417400g"}, true, UserError},
+		{"no hyphens by default", []any{v4Bare}, true, UserError},
+		{"noHyphens", []any{"noHyphens", v4Bare}, false, UserError},
+		{"noHyphens rejects hyphens", []any{"noHyphens", v4}, true, UserError},
+		{"anyHyphens with", []any{"anyHyphens", v4}, false, UserError},
+		{"anyHyphens without", []any{"anyHyphens", v4Bare}, false, UserError},
+		{"not a string", []any{42}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("isUUID", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isUUID(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("isUUID(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestCharacterClassRules(t *testing.T) {
+	tests := []struct {
+		rule      string
+		value     any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"alphanumeric", "abcXYZ019", false, UserError},
+		{"alphanumeric", "", true, UserError},
+		{"alphanumeric", "abc def", true, UserError},
+		{"alphanumeric", "abc_def", true, UserError},
+		{"alphanumeric", "café", true, UserError},
+		{"alphanumeric", 42, true, SystemError},
+		{"numericString", "0123456789", false, UserError},
+		{"numericString", "", true, UserError},
+		{"numericString", "-1", true, UserError},
+		{"numericString", "1.5", true, UserError},
+		{"numericString", "١٢", true, UserError},
+		{"numericString", 12, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s(%q)", tt.rule, tt.value), func(t *testing.T) {
+			err := Check(tt.rule, tt.value).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s(%v) = %v, wantErr %v", tt.rule, tt.value, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%s(%v) classified as %v, want %v", tt.rule, tt.value, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package validator
+
+import (
+	"sync"
+	"time"
+)
+
+// Warning is a failure that was recorded without failing validation.
+type Warning struct {
+	Field         string
+	Rule          string
+	Err           error
+	Grandfathered bool
+}
+
+type grandfathering struct {
+	mu     sync.Mutex
+	until  map[string]time.Time
+	counts map[string]int
+}
+
+// WithClock replaces time.Now as the validator's clock.
+func WithClock(now func() time.Time) Option {
+	return func(cfg *Config) {
+		cfg.Clock = now
+	}
+}
+
+// Grandfather downgrades failures of ruleName to warnings until the deadline
+// passes on the validator's clock, after which they fail validation again.
+func (v *Validator) Grandfather(ruleName string, until time.Time) {
+	v.grandfather.mu.Lock()
+	defer v.grandfather.mu.Unlock()
+
+	v.grandfather.until[ruleName] = until
+}
+
+// GrandfatheredCounts reports how many failures of each grandfathered rule
+// were downgraded to warnings, so it is visible when a grace period can end.
+func (v *Validator) GrandfatheredCounts() map[string]int {
+	v.grandfather.mu.Lock()
+	defer v.grandfather.mu.Unlock()
+
+	counts := make(map[string]int, len(v.grandfather.counts))
+	for rule, n := range v.grandfather.counts {
+		counts[rule] = n
+	}
+
+	return counts
+}
+
+// isGrandfathered reports whether a failure of ruleName is still within its
+// grace period, counting it if so.
+func (v *Validator) isGrandfathered(ruleName string) bool {
+	v.grandfather.mu.Lock()
+	defer v.grandfather.mu.Unlock()
+
+	until, ok := v.grandfather.until[ruleName]
+	if !ok || !v.now().Before(until) {
+		return false
+	}
+
+	v.grandfather.counts[ruleName]++
+	return true
+}
+
+func (ctx *ValidationContext) Warnings() []Warning {
+	ctx.Wait()
+	return append([]Warning(nil), ctx.warnings...)
+}
@@ -0,0 +1,68 @@
+package validator
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestGrandfather(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		until        time.Time
+		rule         string
+		params       []any
+		wantErr      bool
+		wantWarnings int
+	}{
+		{"failure becomes a warning", now.Add(time.Hour), "isEmail", []any{"legacy"}, false, 1},
+		{"passing value has no warning", now.Add(time.Hour), "isEmail", []any{"ada@example.com"}, false, 0},
+		{"other rules still fail", now.Add(time.Hour), "notEmpty", []any{""}, true, 0},
+		{"deadline passed", now.Add(-time.Hour), "isEmail", []any{"legacy"}, true, 0},
+		{"deadline is exclusive", now, "isEmail", []any{"legacy"}, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(WithClock(func() time.Time { return now }))
+			v.Grandfather("isEmail", tt.until)
+
+			ctx := v.newContext()
+			ctx.Field("Email").Check(tt.rule, tt.params...)
+			if err := ctx.Err(); (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+
+			warnings := ctx.Warnings()
+			if len(warnings) != tt.wantWarnings {
+				t.Fatalf("warnings = %v, want %d", warnings, tt.wantWarnings)
+			}
+			for _, w := range warnings {
+				if w.Field != "Email" || w.Rule != "isEmail" || !w.Grandfathered || w.Err == nil {
+					t.Errorf("warning = %+v", w)
+				}
+			}
+		})
+	}
+}
+
+func TestGrandfatheredCounts(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := New(WithClock(func() time.Time { return now }))
+	v.Grandfather("isEmail", now.Add(time.Hour))
+	v.Grandfather("notEmpty", now.Add(time.Hour))
+
+	for _, email := range []string{"a", "b", "ada@example.com"} {
+		ctx := v.newContext()
+		if err := ctx.Check("isEmail", email).Check("notEmpty", "x").Err(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := map[string]int{"isEmail": 2}
+	if got := v.GrandfatheredCounts(); !maps.Equal(got, want) {
+		t.Errorf("GrandfatheredCounts() = %v, want %v", got, want)
+	}
+}
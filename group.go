@@ -0,0 +1,196 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// pendingGroup is a group started by Group that has not been merged yet.
+type pendingGroup struct {
+	done      chan struct{}
+	ctx       ValidationContext
+	at        int
+	recovered any
+	stack     string
+}
+
+// groupHalt stops the groups of a context when the first of them fails, with
+// WithFailFastGroups.
+type groupHalt struct {
+	goctx  context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+	first  *pendingGroup
+}
+
+// WithFailFastGroups makes the first group started by Group to fail stop the
+// other groups of its context: their context.Context is cancelled and checks
+// they have not started yet are skipped. Wait then reports only the failures
+// of that group, which need not be the first declared.
+func WithFailFastGroups() Option {
+	return func(cfg *Config) {
+		cfg.FailFastGroups = true
+	}
+}
+
+// Group runs fn on its own goroutine with a context scoped to the current
+// field, for checks that are slow and independent of the rest, such as
+// remote uniqueness lookups. Groups run concurrently with each other and with
+// the checks that follow them. Their failures are recorded by Wait in
+// declaration order, among those of the checks around them, and a failing
+// group never stops the others unless the validator was created with
+// WithFailFastGroups; when ctx stops at the first failure, a failing group
+// is reported in place of the failures of checks declared after it. Err,
+// Errors and the entry points call Wait themselves.
+//
+// fn must only use the context it is given. It gets a snapshot of the memo,
+// so values it memoizes are not shared with the rest of the pass.
+func (ctx *ValidationContext) Group(fn func(g *ValidationContext)) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	// at is where the group's failures go among those collected so far, so
+	// they keep their place among the checks declared after it
+	g := &pendingGroup{done: make(chan struct{}), ctx: ctx.child(), at: len(ctx.errs)}
+	g.ctx.visited = maps.Clone(g.ctx.visited)
+	g.ctx.memo = maps.Clone(g.ctx.memo)
+	ctx.groups = append(ctx.groups, g)
+
+	halt := ctx.groupHalt()
+	if halt != nil {
+		g.ctx.goctx = halt.goctx
+	}
+
+	run := func() {
+		defer close(g.done)
+		if halt != nil {
+			defer func() {
+				if g.recovered != nil || g.ctx.Wait().failed() {
+					halt.once.Do(func() {
+						halt.first = g
+						halt.cancel()
+					})
+				}
+			}()
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				g.recovered, g.stack = r, trimmedStack()
+			}
+		}()
+
+		fn(&g.ctx)
+	}
+
+	// Explain lists checks in declaration order, so plans are built inline.
+	if ctx.plan != nil {
+		run()
+	} else {
+		go run()
+	}
+
+	return ctx
+}
+
+// Wait blocks until every group started with Group has finished and records
+// their failures on ctx. A panic in a group is re-raised here with
+// WithPanicPropagation and recorded as a RulePanicError otherwise.
+func (ctx *ValidationContext) Wait() *ValidationContext {
+	groups, halt := ctx.groups, ctx.halt
+	ctx.groups, ctx.halt = nil, nil
+
+	for _, g := range groups {
+		<-g.done
+	}
+
+	if halt != nil {
+		halt.cancel()
+		if halt.first != nil {
+			// the other groups were stopped, so only the one that stopped
+			// them has failures worth reporting
+			groups = []*pendingGroup{halt.first}
+		}
+	}
+
+	// Without CollectAll, ctx.err can only come from a check declared after
+	// every pending group, since Group is skipped once ctx has failed.
+	inserted, replaced := 0, false
+	for _, g := range groups {
+		if g.recovered != nil && ctx.validator.propagatePanics {
+			panic(g.recovered)
+		}
+
+		if ctx.fatal != nil || replaced {
+			continue
+		}
+
+		errs := ctx.groupErrors(g)
+		if ctx.fatal != nil || len(errs) == 0 {
+			continue
+		}
+
+		if !ctx.collectAll {
+			ctx.err, replaced = errs[0], true
+			continue
+		}
+
+		at := min(g.at+inserted, len(ctx.errs))
+		ctx.errs = slices.Insert(ctx.errs, at, errs...)
+		inserted += len(errs)
+	}
+
+	return ctx
+}
+
+// groupErrors returns the failures of the finished group g, moving its
+// warnings and any fatal error to ctx. Without CollectAll, a group that
+// collected several failures reports them together.
+func (ctx *ValidationContext) groupErrors(g *pendingGroup) []error {
+	if g.recovered != nil {
+		ctx.validator.logger.Printf("validator: group panicked: %v\n%s", g.recovered, g.stack)
+		var err error = &RulePanicError{Rule: "group", Value: g.recovered, Stack: g.stack}
+		if g.ctx.prefix != "" {
+			err = &FieldError{Field: g.ctx.prefix, Err: err}
+		}
+		return []error{err}
+	}
+
+	ctx.warnings = append(ctx.warnings, g.ctx.warnings...)
+	if g.ctx.fatal != nil {
+		ctx.fatal = g.ctx.fatal
+		return nil
+	}
+
+	errs := g.ctx.Errors()
+	if !ctx.collectAll && len(errs) > 1 {
+		errs = []error{errors.Join(errs...)}
+	}
+
+	return errs
+}
+
+// groupHalt returns the groupHalt shared by the groups of ctx that have not
+// been waited for, or nil without WithFailFastGroups. Plans are built without
+// running rules, so nothing fails and nothing needs stopping.
+func (ctx *ValidationContext) groupHalt() *groupHalt {
+	if !ctx.validator.failFastGroups || ctx.plan != nil {
+		return nil
+	}
+
+	if ctx.halt == nil {
+		goctx, cancel := context.WithCancel(ctx.context())
+		ctx.halt = &groupHalt{goctx: goctx, cancel: cancel}
+	}
+
+	return ctx.halt
+}
+
+// failed reports whether a failure has been recorded on ctx, without waiting
+// for its groups.
+func (ctx *ValidationContext) failed() bool {
+	return ctx.fatal != nil || ctx.err != nil || len(ctx.errs) > 0
+}
@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// slowValidator has a slowCheck rule that takes its first parameter to
+// answer, failing when the value it checks is false, and that stops early
+// when its context is cancelled.
+func slowValidator(opts ...Option) *Validator {
+	v := New(opts...)
+	RegisterRuleCtx(v, "slowCheck", func(goctx context.Context, params []any) error {
+		select {
+		case <-time.After(params[0].(time.Duration)):
+		case <-goctx.Done():
+			return goctx.Err()
+		}
+
+		if !params[1].(bool) {
+			return errors.New("check failed")
+		}
+
+		return nil
+	})
+
+	return v
+}
+
+type slowGroup struct {
+	field string
+	delay time.Duration
+	ok    bool
+}
+
+func TestGroups(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		groups  []slowGroup
+		want    []string
+		maxTime time.Duration
+	}{
+		{"all pass", []Option{WithCollectAll()}, []slowGroup{{"A", 10 * time.Millisecond, true}, {"B", 10 * time.Millisecond, true}}, nil, 0},
+		{"declaration order", []Option{WithCollectAll()}, []slowGroup{{"A", 40 * time.Millisecond, false}, {"B", time.Millisecond, false}}, []string{"A", "B"}, 0},
+		{"failure does not cancel", []Option{WithCollectAll()}, []slowGroup{{"A", 40 * time.Millisecond, false}, {"B", time.Millisecond, false}, {"C", time.Millisecond, true}}, []string{"A", "B"}, 0},
+		{"first failure only", nil, []slowGroup{{"A", 40 * time.Millisecond, false}, {"B", time.Millisecond, false}}, []string{"A"}, 0},
+		{"fail fast", []Option{WithCollectAll(), WithFailFastGroups()}, []slowGroup{{"A", time.Second, true}, {"B", time.Millisecond, false}}, []string{"B"}, 500 * time.Millisecond},
+		{"fail fast all pass", []Option{WithCollectAll(), WithFailFastGroups()}, []slowGroup{{"A", 10 * time.Millisecond, true}, {"B", 10 * time.Millisecond, true}}, nil, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := slowValidator(tt.opts...).newContext()
+			start := time.Now()
+			for _, g := range tt.groups {
+				ctx.Field(g.field).Group(func(gc *ValidationContext) {
+					gc.Check("slowCheck", g.delay, g.ok)
+				})
+			}
+
+			got := failedFields(ctx.Err())
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+			if elapsed := time.Since(start); tt.maxTime > 0 && elapsed > tt.maxTime {
+				t.Errorf("took %v, want under %v", elapsed, tt.maxTime)
+			}
+		})
+	}
+}
+
+func TestGroupsAmongChecks(t *testing.T) {
+	// Each step fails unless its name is listed in pass; lower-case names
+	// are synchronous checks and upper-case ones groups.
+	tests := []struct {
+		name       string
+		collectAll bool
+		steps      []string
+		pass       string
+		want       []string
+	}{
+		{"group, check, group", true, []string{"A", "b", "C"}, "", []string{"A", "b", "C"}},
+		{"checks around a group", true, []string{"a", "B", "c", "d"}, "", []string{"a", "B", "c", "d"}},
+		{"passing steps leave no gap", true, []string{"A", "b", "C", "d"}, "bC", []string{"A", "d"}},
+		{"groups only", true, []string{"A", "B"}, "", []string{"A", "B"}},
+		{"first failure is the group", false, []string{"A", "b", "C"}, "", []string{"A"}},
+		{"first failure is the check", false, []string{"A", "b", "C"}, "A", []string{"b"}},
+		{"later group after a failed check is skipped", false, []string{"a", "B"}, "", []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := slowValidator().newContext()
+			if tt.collectAll {
+				ctx.CollectAll()
+			}
+
+			// groups finish after the checks declared after them
+			for _, step := range tt.steps {
+				ok := strings.Contains(tt.pass, step)
+				if strings.ToUpper(step) == step {
+					ctx.Field(step).Group(func(g *ValidationContext) {
+						g.Check("slowCheck", 20*time.Millisecond, ok)
+					})
+					continue
+				}
+				ctx.Field(step).Check("slowCheck", time.Duration(0), ok)
+			}
+
+			got := failedFields(ctx.Err())
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWhenFnDoesNotWaitForGroups(t *testing.T) {
+	release := make(chan struct{})
+	ctx := New().newContext()
+	ctx.Group(func(g *ValidationContext) {
+		<-release
+		g.Must(func() bool { return false })
+	})
+
+	evaluated := false
+	ctx.WhenFn(func() bool {
+		evaluated = true
+		return true
+	}).End()
+	close(release)
+
+	if !evaluated {
+		t.Error("WhenFn waited for a running group")
+	}
+	if ctx.Err() == nil {
+		t.Error("the group's failure was lost")
+	}
+}
+
+func BenchmarkGroups(b *testing.B) {
+	v := slowValidator()
+	rules := []time.Duration{100 * time.Millisecond, 100 * time.Millisecond}
+
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx := v.newContext()
+			for _, delay := range rules {
+				ctx.Check("slowCheck", delay, true)
+			}
+			if err := ctx.Err(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("groups", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ctx := v.newContext()
+			for _, delay := range rules {
+				ctx.Group(func(g *ValidationContext) {
+					g.Check("slowCheck", delay, true)
+				})
+			}
+			if err := ctx.Err(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMatchesHash(t *testing.T) {
+	sum := sha256.Sum256([]byte("s3cret"))
+	hash := "sha256:" + hex.EncodeToString(sum[:])
+
+	v := New()
+	RegisterHashVerifier(v, "plain:", func(plaintext, hash string) (bool, error) {
+		return "plain:"+plaintext == hash, nil
+	})
+	RegisterHashVerifier(v, "plain:v2:", func(plaintext, hash string) (bool, error) {
+		return "plain:v2:"+strings.ToUpper(plaintext) == hash, nil
+	})
+	RegisterHashVerifier(v, "broken:", func(plaintext, hash string) (bool, error) {
+		return false, errors.New("verifier unavailable")
+	})
+
+	tests := []struct {
+		name       string
+		plaintext  any
+		hash       any
+		wantErr    bool
+		wantSystem bool
+	}{
+		{"sha256 match", "s3cret", hash, false, false},
+		{"sha256 mismatch", "guess", hash, true, false},
+		{"custom prefix match", "abc", "plain:abc", false, false},
+		{"custom prefix mismatch", "abd", "plain:abc", true, false},
+		{"longest prefix wins", "abc", "plain:v2:ABC", false, false},
+		{"no verifier", "s3cret", "md5:abcdef", true, true},
+		{"malformed sha256 hash", "s3cret", "sha256:zz", true, true},
+		{"truncated sha256 hash", "s3cret", hash[:20], true, true},
+		{"verifier error", "s3cret", "broken:x", true, true},
+		{"non-string plaintext", 42, hash, true, true},
+		{"non-string hash", "s3cret", 42, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check("matchesHash", tt.plaintext, tt.hash).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil {
+				return
+			}
+			if got := Classify(err) == SystemError; got != tt.wantSystem {
+				t.Errorf("%v: system error = %v, want %v", err, got, tt.wantSystem)
+			}
+			if s, ok := tt.plaintext.(string); ok && strings.Contains(err.Error(), s) {
+				t.Errorf("error %q reveals the plaintext", err)
+			}
+		})
+	}
+}
+
+func TestHashVerifierInherited(t *testing.T) {
+	parent := New()
+	RegisterHashVerifier(parent, "plain:", func(plaintext, hash string) (bool, error) {
+		return "plain:"+plaintext == hash, nil
+	})
+	child := parent.Child()
+
+	ctx := child.newContext()
+	if err := ctx.Check("matchesHash", "abc", "plain:abc").Err(); err != nil {
+		t.Errorf("child did not use the parent's verifier: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strings"
+)
+
+// Logger receives usage errors: unknown rules, too few parameters and panics
+// recovered from rules. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, args ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Printf(string, ...any) {}
+
+func WithLogger(logger Logger) Option {
+	return func(cfg *Config) {
+		cfg.Logger = logger
+	}
+}
+
+// WithPanicPropagation lets panics from rules crash the caller instead of
+// being recovered into a RulePanicError.
+func WithPanicPropagation() Option {
+	return func(cfg *Config) {
+		cfg.PropagatePanics = true
+	}
+}
+
+// RulePanicError is recorded when a rule panics. Its message does not include
+// the panic value, so it is safe to show to end users; Value and Stack are
+// kept for logging.
+type RulePanicError struct {
+	Rule  string
+	Value any
+	Stack string
+}
+
+func (e *RulePanicError) Error() string {
+	return "rule " + e.Rule + " failed unexpectedly"
+}
+
+func (e *RulePanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// maxStackLines caps the stack kept on a RulePanicError.
+const maxStackLines = 20
+
+func (ctx *ValidationContext) callRule(ruleName string, rule RuleFunc, params []any) (err error) {
+	if !ctx.validator.propagatePanics {
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr := &RulePanicError{Rule: ruleName, Value: r, Stack: trimmedStack()}
+				ctx.validator.logger.Printf("validator: rule %s panicked: %v\n%s", ruleName, r, panicErr.Stack)
+				err = panicErr
+			}
+		}()
+	}
+
+	return rule(params)
+}
+
+// usageError logs a mistake in how the validator is being used and records it
+// as a failure.
+func (ctx *ValidationContext) usageError(err error) {
+	ctx.validator.logger.Printf("validator: %v", err)
+	ctx.fail(asSystemError(err))
+}
+
+// tagError is usageError for mistakes in validate tags, whose messages
+// already name the struct and field.
+func (ctx *ValidationContext) tagError(err error) {
+	ctx.validator.logger.Printf("validator: %v", err)
+	ctx.record(asSystemError(err))
+}
+
+// trimmedStack returns the stack of the panicking goroutine starting at the
+// frame that panicked.
+func trimmedStack() string {
+	lines := strings.Split(strings.TrimSpace(string(debug.Stack())), "\n")
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "panic(") {
+			// skip the panic frame and its file:line
+			lines = lines[min(i+2, len(lines)):]
+			break
+		}
+	}
+
+	if len(lines) > maxStackLines {
+		lines = append(lines[:maxStackLines], fmt.Sprintf("\t... %d more lines", len(lines)-maxStackLines))
+	}
+
+	return strings.Join(lines, "\n")
+}
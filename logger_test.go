@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingLogger keeps every line logged through it.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+type panicForm struct {
+	Code string `validate:"explodes"`
+}
+
+func TestUsageErrorsAreLogged(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(ctx *ValidationContext)
+		wantLog string
+	}{
+		{"panicking rule", func(ctx *ValidationContext) { ctx.Check("explodes", "x") }, "rule explodes panicked: secret panic text"},
+		{"unknown rule", func(ctx *ValidationContext) { ctx.Check("noSuchRule", "x") }, "noSuchRule"},
+		{"wrong arity", func(ctx *ValidationContext) { ctx.Check("greaterThan", 1) }, "greaterThan: expected at least 2 parameters, got 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &recordingLogger{}
+			v := New(WithLogger(logger))
+			RegisterRule(v, "explodes", func(params []any) error { panic("secret panic text") })
+
+			ctx := v.newContext()
+			tt.check(&ctx)
+			err := ctx.Err()
+			if err == nil || Classify(err) != SystemError {
+				t.Fatalf("Err() = %v, want a system error", err)
+			}
+
+			if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], tt.wantLog) {
+				t.Errorf("logged %q, want a line containing %q", logger.lines, tt.wantLog)
+			}
+		})
+	}
+}
+
+func TestRulePanic(t *testing.T) {
+	v := New()
+	RegisterRule(v, "explodes", func(params []any) error { panic(errors.New("secret panic text")) })
+
+	err := v.ValidateStruct(panicForm{Code: "x"})
+	var panicErr *RulePanicError
+	if !errors.As(err, &panicErr) || panicErr.Rule != "explodes" || panicErr.Stack == "" {
+		t.Fatalf("ValidateStruct() = %#v, want a RulePanicError with a stack", err)
+	}
+	if strings.Contains(err.Error(), "secret panic text") {
+		t.Errorf("error %q leaks the panic", err)
+	}
+
+	result := v.ResultOf(err)
+	if result.HTTPStatus() != http.StatusInternalServerError {
+		t.Errorf("HTTPStatus() = %d, want %d", result.HTTPStatus(), http.StatusInternalServerError)
+	}
+	for _, entry := range result.Errors {
+		if strings.Contains(entry.Message, "secret") {
+			t.Errorf("result message %q leaks the panic", entry.Message)
+		}
+	}
+}
+
+func TestPanicPropagation(t *testing.T) {
+	v := New(WithPanicPropagation())
+	RegisterRule(v, "explodes", func(params []any) error { panic("boom") })
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the rule's panic", r)
+		}
+	}()
+
+	ctx := v.newContext()
+	ctx.Check("explodes", "x")
+	t.Error("the panic did not propagate")
+}
+
+func TestPanicsBecomeErrors(t *testing.T) {
+	sentinel := errors.New("sentinel")
+
+	tests := []struct {
+		name      string
+		check     func(ctx *ValidationContext)
+		wantRule  string
+		wantValue func(v any) bool
+		wantIs    error
+	}{
+		{"string panic", func(ctx *ValidationContext) {
+			ctx.Check("panicsWith", "boom")
+		}, "panicsWith", func(v any) bool { return v == "boom" }, nil},
+		{"error panic", func(ctx *ValidationContext) {
+			ctx.Check("panicsWith", sentinel)
+		}, "panicsWith", func(v any) bool { return v == sentinel }, sentinel},
+		{"nil dereference", func(ctx *ValidationContext) {
+			ctx.Check("dereferences", (*int)(nil))
+		}, "dereferences", func(v any) bool { _, ok := v.(runtime.Error); return ok }, nil},
+		{"must", func(ctx *ValidationContext) {
+			ctx.Must(func() bool { panic("in must") })
+		}, "must", func(v any) bool { return v == "in must" }, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			RegisterRule(v, "panicsWith", func(params []any) error { panic(params[0]) })
+			RegisterRule(v, "dereferences", func(params []any) error {
+				_ = *params[0].(*int)
+				return nil
+			})
+
+			ctx := v.newContext()
+			tt.check(&ctx)
+			err := ctx.Err()
+
+			var panicErr *RulePanicError
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("Err() = %v, want a RulePanicError", err)
+			}
+			if panicErr.Rule != tt.wantRule || !tt.wantValue(panicErr.Value) || panicErr.Stack == "" {
+				t.Errorf("got %+v", panicErr)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Errorf("%v does not unwrap to %v", err, tt.wantIs)
+			}
+			if Classify(err) != SystemError {
+				t.Errorf("%v classified as %v, want a system error", err, Classify(err))
+			}
+		})
+	}
+}
+
+func TestBuiltinsDoNotPanicOnBadInput(t *testing.T) {
+	tests := []struct {
+		rule   string
+		params []any
+	}{
+		{"isEmail", []any{42}},
+		{"greaterThan", []any{1}},
+		{"greaterThan", []any{"x", struct{}{}}},
+		{"regex", []any{"abc"}},
+		{"between", []any{nil, nil, nil}},
+		{"mapValues", []any{nil, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			err := Check(tt.rule, tt.params...).Err()
+			var panicErr *RulePanicError
+			if err == nil || errors.As(err, &panicErr) || Classify(err) != SystemError {
+				t.Errorf("%s(%v) = %v, want a system error", tt.rule, tt.params, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+type unit struct {
+	canonical string
+	convert   func(value float64) float64
+	min, max  float64
+}
+
+// RegisterUnit makes name available to the measurement rule. convert turns a
+// value in name into the canonical unit, and min and max bound the physically
+// possible values in that canonical unit.
+func RegisterUnit(v *Validator, name string, canonical string, convert func(value float64) float64, min, max float64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.units[name] = unit{canonical: canonical, convert: convert, min: min, max: max}
+}
+
+func (v *Validator) unit(name string) (unit, bool) {
+	v.mu.RLock()
+	u, ok := v.units[name]
+	v.mu.RUnlock()
+
+	if !ok && v.parent != nil {
+		return v.parent.unit(name)
+	}
+
+	return u, ok
+}
+
+func registerMeasurements(validator *Validator) {
+	RegisterRuleArity(validator, "measurement", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("measurement: expected 2 parameters, got %d", len(params))
+		}
+
+		name, ok := params[0].(string)
+		if !ok {
+			return SystemErrorf("measurement: unsupported type %T for unit", params[0])
+		}
+
+		u, ok := validator.unit(name)
+		if !ok {
+			return SystemErrorf("measurement: unit %q has not been registered", name)
+		}
+
+		for _, p := range params[1:] {
+			rv := reflect.ValueOf(p)
+			var value float64
+			switch rv.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				value = float64(rv.Int())
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+				value = float64(rv.Uint())
+			case reflect.Float32, reflect.Float64:
+				value = rv.Float()
+			default:
+				return SystemErrorf("measurement: unsupported type %T", p)
+			}
+
+			canonical := u.convert(value)
+			if math.IsNaN(canonical) || canonical < u.min || canonical > u.max {
+				return fmt.Errorf("measurement: %v %s is outside the possible range [%v, %v] %s", value, name, u.min, u.max, u.canonical)
+			}
+		}
+
+		return nil
+	})
+
+	identity := func(value float64) float64 { return value }
+
+	RegisterUnit(validator, "K", "K", identity, 0, math.Inf(1))
+	RegisterUnit(validator, "C", "K", func(c float64) float64 { return c + 273.15 }, 0, math.Inf(1))
+	RegisterUnit(validator, "F", "K", func(f float64) float64 { return (f-32)*5/9 + 273.15 }, 0, math.Inf(1))
+	RegisterUnit(validator, "%", "%", identity, 0, 100)
+	RegisterUnit(validator, "Pa", "Pa", identity, 0, math.Inf(1))
+	RegisterUnit(validator, "kPa", "Pa", func(kpa float64) float64 { return kpa * 1000 }, 0, math.Inf(1))
+}
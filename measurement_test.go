@@ -0,0 +1,43 @@
+package validator
+
+import "testing"
+
+func TestMeasurement(t *testing.T) {
+	v := New()
+	RegisterUnit(v, "mph", "m/s", func(mph float64) float64 { return mph * 0.44704 }, 0, 299792458)
+
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"celsius", []any{"C", 21.5}, false, UserError},
+		{"absolute zero", []any{"C", -273.15}, false, UserError},
+		{"below absolute zero", []any{"C", -274}, true, UserError},
+		{"fahrenheit", []any{"F", -459}, false, UserError},
+		{"below absolute zero fahrenheit", []any{"F", -460}, true, UserError},
+		{"negative kelvin", []any{"K", -1}, true, UserError},
+		{"percent", []any{"%", 100}, false, UserError},
+		{"over 100 percent", []any{"%", 100.5}, true, UserError},
+		{"kilopascal", []any{"kPa", uint(101)}, false, UserError},
+		{"registered unit", []any{"mph", 60}, false, UserError},
+		{"faster than light", []any{"mph", 1e9}, true, UserError},
+		{"several values", []any{"C", 20, -300}, true, UserError},
+		{"unknown unit", []any{"furlong", 1}, true, SystemError},
+		{"non-numeric value", []any{"C", "20"}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check("measurement", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("measurement(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("measurement(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
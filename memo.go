@@ -0,0 +1,22 @@
+package validator
+
+type memoEntry struct {
+	value any
+	err   error
+}
+
+// Memo returns the result of compute for key, calling compute at most once
+// per validation pass. Nested contexts share the memo of the context that
+// created them, so handlers for nested values can reuse parsed artifacts.
+// Failed computations are cached too. The memo is not safe for concurrent
+// use; a goroutine working on the same pass must not call Memo.
+func (ctx *ValidationContext) Memo(key any, compute func() (any, error)) (any, error) {
+	if entry, ok := ctx.memo[key]; ok {
+		return entry.value, entry.err
+	}
+
+	value, err := compute()
+	ctx.memo[key] = memoEntry{value: value, err: err}
+
+	return value, err
+}
@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+type memoOuter struct {
+	Inner memoInner
+	Items []memoInner
+}
+
+type memoInner struct{}
+
+func TestMemoIsSharedByNestedContexts(t *testing.T) {
+	tests := []struct {
+		name   string
+		nested func(ctx *ValidationContext, o memoOuter)
+	}{
+		{"Validate", func(ctx *ValidationContext, o memoOuter) { ctx.Field("Inner").Validate(o.Inner) }},
+		{"ValidateEach", func(ctx *ValidationContext, o memoOuter) { ctx.Field("Items").ValidateEach(o.Items) }},
+		{"Each", func(ctx *ValidationContext, o memoOuter) {
+			ctx.Field("Items").Each(o.Items, func(elem any, ctx *ValidationContext) { ctx.Validate(elem) })
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			compute := func() (any, error) {
+				calls++
+				return "parsed", nil
+			}
+
+			v := New()
+			RegisterType(v, func(o memoOuter, ctx *ValidationContext) {
+				tt.nested(ctx, o)
+				ctx.Memo("key", compute)
+			})
+			RegisterType(v, func(_ memoInner, ctx *ValidationContext) {
+				ctx.Memo("key", compute)
+			})
+
+			if err := v.ValidateStruct(memoOuter{Items: []memoInner{{}, {}}}); err != nil {
+				t.Fatal(err)
+			}
+			if calls != 1 {
+				t.Errorf("compute ran %d times, want 1", calls)
+			}
+		})
+	}
+}
+
+func TestMemo(t *testing.T) {
+	failure := errors.New("parse failed")
+
+	tests := []struct {
+		name      string
+		run       func(v *Validator, compute func() (any, error))
+		wantCalls int
+	}{
+		{"once per pass", func(v *Validator, compute func() (any, error)) {
+			ctx := v.newContext()
+			ctx.Memo("url", compute)
+			ctx.Memo("url", compute)
+		}, 1},
+		{"keys are separate", func(v *Validator, compute func() (any, error)) {
+			ctx := v.newContext()
+			ctx.Memo("url", compute)
+			ctx.Memo("host", compute)
+		}, 2},
+		{"passes are separate", func(v *Validator, compute func() (any, error)) {
+			for i := 0; i < 2; i++ {
+				ctx := v.newContext()
+				ctx.Memo("url", compute)
+			}
+		}, 2},
+		{"groups get a snapshot", func(v *Validator, compute func() (any, error)) {
+			ctx := v.newContext()
+			ctx.Memo("url", compute)
+			for i := 0; i < 2; i++ {
+				ctx.Group(func(g *ValidationContext) {
+					g.Memo("url", compute)
+					g.Memo("host", compute)
+				})
+			}
+			ctx.Wait()
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			tt.run(New(), func() (any, error) {
+				calls.Add(1)
+				return "parsed", nil
+			})
+			if got := int(calls.Load()); got != tt.wantCalls {
+				t.Errorf("compute ran %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("errors are cached", func(t *testing.T) {
+		calls := 0
+		ctx := New().newContext()
+		for i := 0; i < 2; i++ {
+			value, err := ctx.Memo("url", func() (any, error) {
+				calls++
+				return nil, failure
+			})
+			if value != nil || err != failure {
+				t.Errorf("Memo() = %v, %v, want the failure", value, err)
+			}
+		}
+		if calls != 1 {
+			t.Errorf("compute ran %d times, want 1", calls)
+		}
+	})
+}
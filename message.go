@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetMessage sets the message used whenever ruleName fails, in place of the
+// rule's own error. The message may contain placeholders, as in Messagef.
+func (v *Validator) SetMessage(ruleName string, template string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.messages[ruleName] = template
+}
+
+// Messagef is Message with positional placeholders filled in from the params
+// of the preceding Check, so Check("minLength", name, 3).Messagef("must be at
+// least {1} characters") reads "must be at least 3 characters". {{ and }}
+// produce literal braces, and placeholders without a matching param are left
+// as they are.
+func (ctx *ValidationContext) Messagef(template string) *ValidationContext {
+	if !ctx.lastFailed {
+		return ctx
+	}
+
+	last := ctx.err
+	if ctx.collectAll {
+		last = ctx.errs[len(ctx.errs)-1]
+	}
+
+	var params []any
+	var re *RuleError
+	if errors.As(last, &re) {
+		params = re.Params
+	}
+
+	return ctx.Message(expandMessage(template, params))
+}
+
+func expandMessage(template string, params []any) string {
+	var b strings.Builder
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+		switch {
+		case c == '{' && strings.HasPrefix(template[i:], "{{"):
+			b.WriteByte('{')
+			i++
+		case c == '}' && strings.HasPrefix(template[i:], "}}"):
+			b.WriteByte('}')
+			i++
+		case c == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				b.WriteString(template[i:])
+				return b.String()
+			}
+
+			placeholder := template[i : i+end+1]
+			n, err := strconv.Atoi(placeholder[1 : len(placeholder)-1])
+			if err != nil || n < 0 || n >= len(params) {
+				b.WriteString(placeholder)
+			} else {
+				fmt.Fprint(&b, params[n])
+			}
+			i += end
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String()
+}
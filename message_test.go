@@ -0,0 +1,175 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		name       string
+		collectAll bool
+		check      func(ctx *ValidationContext)
+		want       []string
+	}{
+		{"replaces the failure", false, func(ctx *ValidationContext) {
+			ctx.Field("Age").Check("greaterThan", 18, 12).Message("too young")
+		}, []string{"Age: too young"}},
+		{"passing check", false, func(ctx *ValidationContext) {
+			ctx.Field("Age").Check("greaterThan", 18, 20).Message("too young")
+		}, nil},
+		{"earlier failure is kept", false, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.Field("Age").Check("greaterThan", 18, 20).Message("too young")
+		}, []string{"Email: must be a valid email address"}},
+		{"collect all, earlier failure is kept", true, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.Field("Age").Check("greaterThan", 18, 20).Message("too young")
+		}, []string{"Email: must be a valid email address"}},
+		{"collect all, replaces only the last", true, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.Field("Age").Check("greaterThan", 18, 12).Message("too young")
+		}, []string{"Email: must be a valid email address", "Age: too young"}},
+		{"skipped by When", false, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.When(false).Field("Age").Check("greaterThan", 18, 12).Message("too young").End()
+		}, []string{"Email: must be a valid email address"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New().newContext()
+			if tt.collectAll {
+				ctx.CollectAll()
+			}
+			tt.check(&ctx)
+
+			var got []string
+			for _, err := range ctx.Errors() {
+				got = append(got, err.Error())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("errors = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("error %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMessageKeepsCause(t *testing.T) {
+	ctx := New().newContext()
+	err := ctx.Check("greaterThan", 18, 12).Message("too young").Err()
+
+	var re *RuleError
+	if !errors.As(err, &re) || re.Rule != "greaterThan" {
+		t.Errorf("got %v, want the RuleError to survive Message", err)
+	}
+}
+
+func TestExpandMessage(t *testing.T) {
+	tests := []struct {
+		template string
+		params   []any
+		want     string
+	}{
+		{"must be at least {1} characters", []any{"ab", 3}, "must be at least 3 characters"},
+		{"{0} is not {1}", []any{"a", "b"}, "a is not b"},
+		{"no placeholders", []any{1}, "no placeholders"},
+		{"{{1}} is literal", []any{"a", "b"}, "{1} is literal"},
+		{"{{{1}}}", []any{"a", "b"}, "{b}"},
+		{"only {2} of two", []any{"a", "b"}, "only {2} of two"},
+		{"negative {-1}", []any{"a"}, "negative {-1}"},
+		{"named {max}", []any{"a"}, "named {max}"},
+		{"unterminated {1", []any{"a", "b"}, "unterminated {1"},
+		{"lone } brace", nil, "lone } brace"},
+		{"{0}", nil, "{0}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			if got := expandMessage(tt.template, tt.params); got != tt.want {
+				t.Errorf("expandMessage(%q, %v) = %q, want %q", tt.template, tt.params, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessagef(t *testing.T) {
+	tests := []struct {
+		name       string
+		collectAll bool
+		check      func(ctx *ValidationContext)
+		want       []string
+	}{
+		{"expands params", false, func(ctx *ValidationContext) {
+			ctx.Field("Name").Check("minLength", "ab", 3).Messagef("must be at least {1} characters")
+		}, []string{"Name: must be at least 3 characters"}},
+		{"escapes braces", false, func(ctx *ValidationContext) {
+			ctx.Check("minLength", "ab", 3).Messagef("{{min}} is {1}")
+		}, []string{"{min} is 3"}},
+		{"missing param", false, func(ctx *ValidationContext) {
+			ctx.Check("minLength", "ab", 3).Messagef("between {1} and {5}")
+		}, []string{"between 3 and {5}"}},
+		{"passing check", false, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.Field("Name").Check("minLength", "abc", 3).Messagef("must be at least {1} characters")
+		}, []string{"Email: must be a valid email address"}},
+		{"collect all, passing check", true, func(ctx *ValidationContext) {
+			ctx.Field("Email").Check("isEmail", "x")
+			ctx.Field("Name").Check("minLength", "abc", 3).Messagef("must be at least {1} characters")
+		}, []string{"Email: must be a valid email address"}},
+		{"collect all, uses its own params", true, func(ctx *ValidationContext) {
+			ctx.Field("Age").Check("greaterThan", 18, 12)
+			ctx.Field("Name").Check("minLength", "ab", 3).Messagef("must be at least {1} characters")
+		}, []string{"Age: greaterThan: parameter at position 2 (= 12) is not greater than 18", "Name: must be at least 3 characters"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New().newContext()
+			if tt.collectAll {
+				ctx.CollectAll()
+			}
+			tt.check(&ctx)
+
+			var got []string
+			for _, err := range ctx.Errors() {
+				got = append(got, err.Error())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("errors = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetMessage(t *testing.T) {
+	v := New()
+	v.SetMessage("minLength", "needs {1} characters, got {{{0}}}")
+	v.SetMessage("isEmail", "is not an email")
+
+	tests := []struct {
+		rule   string
+		params []any
+		want   string
+	}{
+		{"minLength", []any{"ab", 3}, "needs 3 characters, got {ab}"},
+		{"isEmail", []any{"x"}, "is not an email"},
+		{"notEmpty", []any{""}, "required rule failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check(tt.rule, tt.params...).Err()
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
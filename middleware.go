@@ -0,0 +1,32 @@
+package validator
+
+// Middleware wraps a rule, e.g. to time, log or retry it.
+type Middleware func(next RuleFunc) RuleFunc
+
+// Use adds mw around every rule run by Check. Middleware added first is the
+// outermost, so it sees every call before the middleware added after it.
+func (v *Validator) Use(mw Middleware) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.middleware = append(v.middleware, mw)
+}
+
+// wrap applies the middleware added with Use to rule. The middleware of a
+// parent validator wraps that of its children. The chain is built from a
+// snapshot taken under the lock, so middleware may use the validator.
+func (v *Validator) wrap(rule RuleFunc) RuleFunc {
+	v.mu.RLock()
+	middleware := v.middleware
+	v.mu.RUnlock()
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		rule = middleware[i](rule)
+	}
+
+	if v.parent != nil {
+		return v.parent.wrap(rule)
+	}
+
+	return rule
+}
@@ -0,0 +1,153 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+// tracing returns middleware that appends name to trace around each call.
+func tracing(name string, trace *[]string) Middleware {
+	return func(next RuleFunc) RuleFunc {
+		return func(params []any) error {
+			*trace = append(*trace, name+">")
+			err := next(params)
+			*trace = append(*trace, "<"+name)
+			return err
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		setup     func(v *Validator, trace *[]string) *Validator
+		check     func(ctx *ValidationContext)
+		wantTrace []string
+		wantErr   bool
+	}{
+		{"no middleware", func(v *Validator, trace *[]string) *Validator {
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "x")
+		}, nil, false},
+		{"wraps every call", func(v *Validator, trace *[]string) *Validator {
+			v.Use(tracing("a", trace))
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "x").Check("greaterThan", 1, 2)
+		}, []string{"a>", "<a", "a>", "<a"}, false},
+		{"first added is outermost", func(v *Validator, trace *[]string) *Validator {
+			v.Use(tracing("a", trace))
+			v.Use(tracing("b", trace))
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "x")
+		}, []string{"a>", "b>", "<b", "<a"}, false},
+		{"sees failures", func(v *Validator, trace *[]string) *Validator {
+			v.Use(func(next RuleFunc) RuleFunc {
+				return func(params []any) error {
+					err := next(params)
+					if err != nil {
+						*trace = append(*trace, "failed")
+					}
+					return err
+				}
+			})
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "")
+		}, []string{"failed"}, true},
+		{"can replace the outcome", func(v *Validator, trace *[]string) *Validator {
+			v.Use(func(next RuleFunc) RuleFunc {
+				return func(params []any) error { return errors.New("denied") }
+			})
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "x")
+		}, nil, true},
+		{"parent wraps child", func(v *Validator, trace *[]string) *Validator {
+			v.Use(tracing("parent", trace))
+			child := v.Child()
+			child.Use(tracing("child", trace))
+			return child
+		}, func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "x")
+		}, []string{"parent>", "child>", "<child", "<parent"}, false},
+		{"unknown rules are not wrapped", func(v *Validator, trace *[]string) *Validator {
+			v.Use(tracing("a", trace))
+			return v
+		}, func(ctx *ValidationContext) {
+			ctx.Check("noSuchRule")
+		}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var trace []string
+			v := tt.setup(New(), &trace)
+
+			ctx := v.newContext()
+			tt.check(&ctx)
+			if err := ctx.Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+			if !slices.Equal(trace, tt.wantTrace) {
+				t.Errorf("trace = %v, want %v", trace, tt.wantTrace)
+			}
+		})
+	}
+}
+
+func TestMiddlewareCallCount(t *testing.T) {
+	v := New(WithCollectAll())
+	calls := 0
+	v.Use(func(next RuleFunc) RuleFunc {
+		return func(params []any) error {
+			calls++
+			return next(params)
+		}
+	})
+
+	type account struct {
+		Name  string `validate:"notEmpty"`
+		Email string `validate:"isEmail"`
+		Age   int    `validate:"greaterThan=17"`
+	}
+
+	if err := v.ValidateStruct(account{Name: "a", Email: "a@example.com", Age: 30}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("middleware ran %d times, want once per rule (3)", calls)
+	}
+}
+
+func TestMiddlewareBuiltOutsideLock(t *testing.T) {
+	v := New()
+	v.Use(func(next RuleFunc) RuleFunc {
+		// registering while the chain is built would deadlock under the lock
+		RegisterRule(v, "seen", func(params []any) error { return nil })
+		return next
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		ctx := v.newContext()
+		done <- ctx.Check("notEmpty", "x").Err()
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("building the middleware chain deadlocked")
+	}
+
+	if !v.HasRule("seen") {
+		t.Error("middleware could not register a rule")
+	}
+}
@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// ParamRef is a rule parameter resolved by name at validation time, so one
+// Validator can serve callers with different limits. A reference without a
+// value fails validation as a system error; Compile finds them up front.
+type ParamRef struct {
+	Name string
+}
+
+func Param(name string) ParamRef {
+	return ParamRef{Name: name}
+}
+
+// ValidateWithParams validates value like ValidateStruct, resolving ParamRef
+// rule parameters from params. It is short for ValidateStruct with
+// WithParams.
+func (v *Validator) ValidateWithParams(value any, params map[string]any) error {
+	return v.ValidateStruct(value, WithParams(params))
+}
+
+type paramsKey struct{}
+
+// ContextWithParams returns a copy of goctx carrying params, so ParamRefs can
+// be resolved from a request's context, e.g. one set by tenant middleware.
+// Params given with WithParams take precedence.
+func ContextWithParams(goctx context.Context, params map[string]any) context.Context {
+	return context.WithValue(goctx, paramsKey{}, params)
+}
+
+// param looks up the value of a ParamRef in the params of the run and then in
+// those of its context.Context.
+func (ctx *ValidationContext) param(name string) (any, bool) {
+	if value, ok := ctx.params[name]; ok {
+		return value, true
+	}
+
+	params, _ := ctx.context().Value(paramsKey{}).(map[string]any)
+	value, ok := params[name]
+	return value, ok
+}
+
+// Compile reports, ahead of validation, every ParamRef used in validating
+// values of sample's type that is not one of params, the names callers
+// declare they supply. It looks at the validate tags of the type and the
+// types nested in it, and at the checks Explain reports for sample, which
+// include those made by handlers.
+func (v *Validator) Compile(sample any, params ...string) error {
+	plan, err := v.Explain(sample)
+	if plan == nil {
+		return err
+	}
+
+	errs := []error{err}
+	reported := make(map[string]bool)
+	report := func(where string, p any) {
+		ref, ok := p.(ParamRef)
+		if !ok || slices.Contains(params, ref.Name) || reported[ref.Name] {
+			return
+		}
+
+		reported[ref.Name] = true
+		errs = append(errs, SystemErrorf("%s: unresolved parameter %q", where, ref.Name))
+	}
+
+	tagParamRefs(reflect.TypeOf(sample), map[reflect.Type]bool{}, report)
+	for _, check := range plan {
+		for _, p := range check.Params {
+			report(joinPath(check.Field, check.Rule), p)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// tagParamRefs calls report with every parameter in the validate tags of typ
+// and the types nested in it.
+func tagParamRefs(typ reflect.Type, seen map[reflect.Type]bool, report func(where string, p any)) {
+	typ = containedType(typ)
+	if typ == nil || typ.Kind() != reflect.Struct || seen[typ] {
+		return
+	}
+	seen[typ] = true
+
+	for _, f := range schemaFor(typ).fields {
+		for _, rule := range f.rules {
+			for _, p := range rule.params {
+				report(fmt.Sprintf("validate tag on %v.%s", typ, f.field.Name), p)
+			}
+		}
+
+		tagParamRefs(f.field.Type, seen, report)
+	}
+}
+
+// resolveParams substitutes ParamRefs, returning an error for any reference
+// the caller did not supply a value for.
+func (ctx *ValidationContext) resolveParams(ruleName string, params []any) ([]any, error) {
+	var resolved []any
+	for i, p := range params {
+		ref, ok := p.(ParamRef)
+		if !ok {
+			continue
+		}
+
+		value, ok := ctx.param(ref.Name)
+		if !ok {
+			return nil, fmt.Errorf("%s: unresolved parameter %q", ruleName, ref.Name)
+		}
+
+		if resolved == nil {
+			resolved = append([]any(nil), params...)
+		}
+		resolved[i] = value
+	}
+
+	if resolved == nil {
+		return params, nil
+	}
+
+	return resolved, nil
+}
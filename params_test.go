@@ -0,0 +1,91 @@
+package validator
+
+import (
+	"context"
+	"testing"
+)
+
+type paramsTeam struct {
+	Size  int `validate:"between=1:{maxTeamSize}"`
+	Owner paramsMember
+}
+
+type paramsMember struct {
+	Email string `validate:"notOneOf={bannedEmail}"`
+}
+
+type paramsOrg struct {
+	Seats int
+}
+
+func TestParamResolution(t *testing.T) {
+	limits := map[string]any{"maxTeamSize": 25, "bannedEmail": "root@example.com"}
+
+	tests := []struct {
+		name      string
+		value     paramsTeam
+		opts      []RunOption
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"within limit", paramsTeam{Size: 25}, []RunOption{WithParams(limits)}, false, UserError},
+		{"over limit", paramsTeam{Size: 26}, []RunOption{WithParams(limits)}, true, UserError},
+		{"from context", paramsTeam{Size: 26}, []RunOption{WithContext(ContextWithParams(context.Background(), limits))}, true, UserError},
+		{"run params first", paramsTeam{Size: 26}, []RunOption{
+			WithParams(map[string]any{"maxTeamSize": 30}),
+			WithContext(ContextWithParams(context.Background(), limits)),
+		}, false, UserError},
+		{"unresolved", paramsTeam{Size: 3}, nil, true, SystemError},
+		{"nested", paramsTeam{Size: 3, Owner: paramsMember{Email: "root@example.com"}}, []RunOption{WithParams(limits)}, true, UserError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().ValidateStruct(tt.value, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%v classified as %v, want %v", err, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	v := New()
+	RegisterType(v, func(o paramsOrg, ctx *ValidationContext) {
+		ctx.Field("Seats").Check("lessThan", Param("maxSeats"), o.Seats)
+	})
+
+	tests := []struct {
+		name     string
+		sample   any
+		declared []string
+		want     int
+	}{
+		{"all declared", paramsTeam{}, []string{"maxTeamSize", "bannedEmail"}, 0},
+		{"nested tag undeclared", paramsTeam{}, []string{"maxTeamSize"}, 1},
+		{"nothing declared", paramsTeam{}, nil, 2},
+		{"handler", paramsOrg{}, nil, 1},
+		{"handler declared", paramsOrg{}, []string{"maxSeats"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Compile(tt.sample, tt.declared...)
+			if got := len(flattenErrors(err)); got != tt.want {
+				t.Fatalf("Compile() = %v, want %d errors", err, tt.want)
+			}
+			if err != nil && Classify(err) != SystemError {
+				t.Errorf("Compile() error classified as %v", Classify(err))
+			}
+		})
+	}
+}
+
+func TestCompileUnknownType(t *testing.T) {
+	if err := New().Compile(42); err == nil {
+		t.Errorf("Compile(42) = %v, want an error", err)
+	}
+}
@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Step is a single unit of a Pipeline. Steps are immutable once constructed,
+// so pipelines can be built once and shared across goroutines.
+type Step interface {
+	run(ctx *ValidationContext, subject reflect.Value)
+}
+
+// Pipeline declares validation as data. A Pipeline is itself a Step, so
+// pipelines can be embedded in one another.
+type Pipeline []Step
+
+func (p Pipeline) run(ctx *ValidationContext, subject reflect.Value) {
+	for _, step := range p {
+		step.run(ctx, subject)
+	}
+}
+
+type ruleStep struct {
+	name   string
+	params []any
+}
+
+type fieldStep struct {
+	name  string
+	steps Pipeline
+}
+
+type nestedStep struct {
+	name string
+}
+
+type whenStep struct {
+	cond  func(value any) bool
+	steps Pipeline
+}
+
+// Rule checks the current subject with a registered rule. The subject is
+// passed where the rule expects it, as in tags: after params, so
+// Rule("greaterThan", 17) runs greaterThan(17, subject), or ahead of them for
+// rules such as regex and oneOf.
+func Rule(name string, params ...any) Step {
+	return ruleStep{name: name, params: append([]any(nil), params...)}
+}
+
+// Field runs steps against the named struct field of the current subject.
+func Field(name string, steps ...Step) Step {
+	return fieldStep{name: name, steps: append(Pipeline(nil), steps...)}
+}
+
+// Nested validates the named struct field with its registered type handler.
+func Nested(fieldName string) Step {
+	return nestedStep{name: fieldName}
+}
+
+// When runs steps only if cond reports true for the current subject.
+func When(cond func(value any) bool, steps ...Step) Step {
+	return whenStep{cond: cond, steps: append(Pipeline(nil), steps...)}
+}
+
+func (v *Validator) RunPipeline(value any, p Pipeline) error {
+	ctx := v.newContext()
+	p.run(&ctx, reflect.ValueOf(value))
+
+	return v.finish(&ctx, value)
+}
+
+func (s ruleStep) run(ctx *ValidationContext, subject reflect.Value) {
+	ctx.Check(s.name, withSubject(s.name, subjectInterface(subject), s.params)...)
+}
+
+func (s fieldStep) run(ctx *ValidationContext, subject reflect.Value) {
+	if ctx.skip() {
+		return
+	}
+
+	field, err := fieldByName(subject, s.name)
+	if err != nil {
+		ctx.usageError(err)
+		return
+	}
+
+	prefix, current := ctx.prefix, ctx.field
+	ctx.Field(s.name)
+	ctx.prefix = ctx.field
+	s.steps.run(ctx, field)
+	ctx.prefix, ctx.field = prefix, current
+}
+
+func (s nestedStep) run(ctx *ValidationContext, subject reflect.Value) {
+	if ctx.skip() {
+		return
+	}
+
+	field, err := fieldByName(subject, s.name)
+	if err != nil {
+		ctx.usageError(err)
+		return
+	}
+
+	current := ctx.field
+	ctx.Field(s.name)
+	ctx.Validate(subjectInterface(field))
+	ctx.field = current
+}
+
+func (s whenStep) run(ctx *ValidationContext, subject reflect.Value) {
+	if ctx.skip() || !s.cond(subjectInterface(subject)) {
+		return
+	}
+
+	s.steps.run(ctx, subject)
+}
+
+func subjectInterface(subject reflect.Value) any {
+	if !subject.IsValid() {
+		return nil
+	}
+
+	return subject.Interface()
+}
+
+func fieldByName(subject reflect.Value, name string) (reflect.Value, error) {
+	if !subject.IsValid() {
+		return reflect.Value{}, fmt.Errorf("cannot read field %s of nil", name)
+	}
+
+	for subject.Kind() == reflect.Pointer || subject.Kind() == reflect.Interface {
+		if subject.IsNil() {
+			return reflect.Value{}, fmt.Errorf("cannot read field %s of nil %v", name, subject.Type())
+		}
+		subject = subject.Elem()
+	}
+
+	if subject.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("cannot read field %s of non-struct %v", name, subject.Type())
+	}
+
+	field := subject.FieldByName(name)
+	if !field.IsValid() {
+		return reflect.Value{}, fmt.Errorf("%v has no field %s", subject.Type(), name)
+	}
+
+	if !field.CanInterface() {
+		return reflect.Value{}, fmt.Errorf("field %s of %v is unexported", name, subject.Type())
+	}
+
+	return field, nil
+}
@@ -0,0 +1,34 @@
+package validator
+
+import "testing"
+
+type pipelineTicket struct {
+	Status string
+	Age    int
+}
+
+func TestPipelineRuleSubjectPosition(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		step    Step
+		value   pipelineTicket
+		wantErr bool
+	}{
+		{"subject last passes", "Age", Rule("greaterThan", 17), pipelineTicket{Age: 18}, false},
+		{"subject last fails", "Age", Rule("greaterThan", 17), pipelineTicket{Age: 17}, true},
+		{"regex passes", "Status", Rule("regex", "^o"), pipelineTicket{Status: "open"}, false},
+		{"regex fails", "Status", Rule("regex", "^o"), pipelineTicket{Status: "closed"}, true},
+		{"oneOf passes", "Status", Rule("oneOf", "open", "closed"), pipelineTicket{Status: "closed"}, false},
+		{"oneOf fails", "Status", Rule("oneOf", "open", "closed"), pipelineTicket{Status: "pending"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New().RunPipeline(tt.value, Pipeline{Field(tt.field, tt.step)})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RunPipeline() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
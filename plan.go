@@ -0,0 +1,35 @@
+package validator
+
+// PlannedCheck is a single check the engine would evaluate.
+type PlannedCheck struct {
+	Field  string `json:"field,omitempty"`
+	Rule   string `json:"rule"`
+	Params []any  `json:"params,omitempty"`
+}
+
+// Plan is the ordered list of checks reported by Explain.
+type Plan []PlannedCheck
+
+func (p *Plan) add(field string, rule string, params []any) {
+	*p = append(*p, PlannedCheck{
+		Field:  field,
+		Rule:   rule,
+		Params: append([]any(nil), params...),
+	})
+}
+
+// Explain reports the checks that validating value would evaluate, without
+// running any rule. Handlers still execute, but their Check and Must calls
+// are recorded instead of evaluated. Mistakes found on the way, such as
+// malformed tags, are returned alongside the plan of everything else.
+func (v *Validator) Explain(value any) (Plan, error) {
+	plan := Plan{}
+
+	ctx := v.newContext()
+	ctx.plan = &plan
+	if !v.validateStruct(&ctx, value) {
+		return nil, SystemErrorf("no type handler registered for %T", value)
+	}
+
+	return plan, ctx.Err()
+}
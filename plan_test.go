@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type planUser struct {
+	Name string `validate:"minLength=2"`
+	Age  int    `validate:"greaterThan=17"`
+}
+
+type planBrokenTag struct {
+	Name string `validate:"minLength=2"`
+	Age  int    `validate:"oneOf='1:2"`
+}
+
+type planHandled struct {
+	Name string
+}
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		wantRules []string
+		wantErr   bool
+	}{
+		{"tags", planUser{}, []string{"minLength", "greaterThan"}, false},
+		{"pointer", &planUser{}, []string{"minLength", "greaterThan"}, false},
+		{"malformed tag", planBrokenTag{}, []string{"minLength"}, true},
+		{"no handler", 42, nil, true},
+		{"nil", nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := New().Explain(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Explain() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && (Classify(err) != SystemError || !HasSystemError(err)) {
+				t.Errorf("Explain() error %v is not classified as a system error", err)
+			}
+
+			var rules []string
+			for _, check := range plan {
+				rules = append(rules, check.Rule)
+			}
+			if len(rules) != len(tt.wantRules) {
+				t.Fatalf("planned %v, want %v", rules, tt.wantRules)
+			}
+			for i := range rules {
+				if rules[i] != tt.wantRules[i] {
+					t.Errorf("planned %v, want %v", rules, tt.wantRules)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainDoesNotRunRules(t *testing.T) {
+	v := New()
+	ran := false
+	RegisterRule(v, "planSpy", func(params []any) error {
+		ran = true
+		return nil
+	})
+	RegisterType(v, func(u planHandled, ctx *ValidationContext) {
+		ctx.Field("Name").Check("planSpy", u.Name)
+	})
+
+	plan, err := v.Explain(planHandled{Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Error("Explain ran a rule")
+	}
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"field":"Name","rule":"planSpy","params":["x"]}]`; string(data) != want {
+		t.Errorf("plan = %s, want %s", data, want)
+	}
+}
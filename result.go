@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Result is the JSON shape of validation failures, returned by ValidateAll and
+// ResultOf and rendered by MarshalErrors, e.g.
+//
+//	{"errors":[{"field":"Email","rule":"isEmail","message":"..."}]}
+type Result struct {
+	Errors   []ResultEntry `json:"errors"`
+	Warnings []ResultEntry `json:"warnings,omitempty"`
+
+	hasSystemError bool
+}
+
+// ResultEntry is one failed check. Value is the offending value, omitted for
+// sensitive fields and when the validator was created WithRedactedValues.
+// Messages of system errors are replaced with a generic one.
+type ResultEntry struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+	Value   any    `json:"value,omitempty"`
+}
+
+// WithRedactedValues leaves offending values out of every Result.
+func WithRedactedValues() Option {
+	return func(cfg *Config) {
+		cfg.RedactValues = true
+	}
+}
+
+// ValidateAll validates value like ValidateStruct, collecting every failure
+// into a Result instead of an error.
+func (v *Validator) ValidateAll(value any, opts ...RunOption) Result {
+	ctx := v.newRun(opts)
+	ctx.CollectAll()
+
+	result := v.ResultOf(v.validate(&ctx, value))
+	for _, w := range ctx.Warnings() {
+		entry := ResultEntry{Field: w.Field, Rule: w.Rule, Message: w.Err.Error()}
+		if Classify(w.Err) == SystemError {
+			entry.Message = internalErrorMessage
+		}
+		result.Warnings = append(result.Warnings, entry)
+	}
+
+	return result
+}
+
+// ResultOf converts err, as returned by the validation entry points, into a
+// Result, so failures from any entry point render in the same JSON shape.
+func (v *Validator) ResultOf(err error) Result {
+	return newResult(err, !v.redactValues)
+}
+
+// newResult converts err into a Result, with offending values when values is
+// true.
+func newResult(err error, values bool) Result {
+	result := Result{Errors: []ResultEntry{}}
+	for _, e := range flattenErrors(err) {
+		result.Errors = append(result.Errors, resultEntry(e, values))
+		if Classify(e) == SystemError {
+			result.hasSystemError = true
+		}
+	}
+
+	return result
+}
+
+func resultEntry(err error, values bool) ResultEntry {
+	ve := AsValidationErrors(err)[0]
+	entry := ResultEntry{Field: ve.Field, Rule: ve.Rule, Message: ve.Message}
+
+	var re *RuleError
+	if values && Classify(err) == UserError && errors.As(err, &re) && !re.sensitive {
+		entry.Value = subjectOf(re.Rule, re.Params)
+	}
+
+	return entry
+}
+
+func (r Result) Empty() bool {
+	return len(r.Errors) == 0
+}
+
+// HTTPStatus returns 200 for an empty Result, 500 if any failure is a system
+// error and 422 otherwise.
+func (r Result) HTTPStatus() int {
+	switch {
+	case r.Empty():
+		return http.StatusOK
+	case r.hasSystemError:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusUnprocessableEntity
+	}
+}
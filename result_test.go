@@ -0,0 +1,81 @@
+package validator
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+type resultSignup struct {
+	Email    string `validate:"isEmail" json:"email"`
+	Password string `validate:"minLength=8" sensitive:"true"`
+	Age      int    `validate:"greaterThan=17"`
+}
+
+type resultBroken struct {
+	Name string `validate:"noSuchRule"`
+}
+
+func TestValidateAllResult(t *testing.T) {
+	tests := []struct {
+		name       string
+		options    []Option
+		value      any
+		wantStatus int
+		wantJSON   string
+	}{
+		{
+			"valid", nil,
+			resultSignup{Email: "ada@example.com", Password: "correct horse", Age: 36},
+			http.StatusOK,
+			`{"errors":[]}`,
+		},
+		{
+			"every failure", nil,
+			resultSignup{Email: "ada", Password: "hunter2", Age: 12},
+			http.StatusUnprocessableEntity,
+			`{"errors":[` +
+				`{"field":"Email","rule":"isEmail","message":"must be a valid email address","value":"ada"},` +
+				`{"field":"Password","rule":"minLength","message":"must be at least 8 characters, got 7"},` +
+				`{"field":"Age","rule":"greaterThan","message":"greaterThan: parameter at position 2 (= 12) is not greater than 17","value":12}]}`,
+		},
+		{
+			"json field names", []Option{WithJSONTagNames()},
+			resultSignup{Email: "ada", Password: "correct horse", Age: 36},
+			http.StatusUnprocessableEntity,
+			`{"errors":[{"field":"email","rule":"isEmail","message":"must be a valid email address","value":"ada"}]}`,
+		},
+		{
+			"redacted values", []Option{WithRedactedValues()},
+			resultSignup{Email: "ada", Password: "correct horse", Age: 36},
+			http.StatusUnprocessableEntity,
+			`{"errors":[{"field":"Email","rule":"isEmail","message":"must be a valid email address"}]}`,
+		},
+		{
+			"system error", nil,
+			resultBroken{Name: "x"},
+			http.StatusInternalServerError,
+			`{"errors":[{"message":"internal error"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := New(tt.options...).ValidateAll(tt.value)
+			if got := result.HTTPStatus(); got != tt.wantStatus {
+				t.Errorf("HTTPStatus() = %d, want %d", got, tt.wantStatus)
+			}
+			if result.Empty() != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("Empty() = %v with status %d", result.Empty(), tt.wantStatus)
+			}
+
+			data, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != tt.wantJSON {
+				t.Errorf("ValidateAll() =\n%s\nwant\n%s", data, tt.wantJSON)
+			}
+		})
+	}
+}
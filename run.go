@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// RunOption configures a single validation run, so one call can combine a
+// locale, parameters, a context.Context and a scenario, e.g.
+//
+//	err := v.ValidateStruct(value, validator.WithLocale("fr"), validator.WithParams(limits))
+type RunOption func(ctx *ValidationContext)
+
+// WithLocale renders failure messages in locale.
+func WithLocale(locale string) RunOption {
+	return func(ctx *ValidationContext) {
+		ctx.locale = locale
+	}
+}
+
+// WithParams resolves ParamRef rule parameters from params, ahead of those
+// carried by the context.Context; see ContextWithParams.
+func WithParams(params map[string]any) RunOption {
+	return func(ctx *ValidationContext) {
+		ctx.params = params
+	}
+}
+
+// WithContext passes goctx to every rule registered with RegisterRuleCtx,
+// including those run by Check and by tags. Once goctx is done, validation
+// stops before the next rule runs and the context's error is returned as a
+// system error.
+func WithContext(goctx context.Context) RunOption {
+	return func(ctx *ValidationContext) {
+		ctx.goctx = goctx
+	}
+}
+
+// WithScenario validates in scenario; see ValidateScenario.
+func WithScenario(scenario string) RunOption {
+	return func(ctx *ValidationContext) {
+		ctx.scenario = scenario
+	}
+}
+
+// newRun creates the context of a validation run configured by opts.
+func (v *Validator) newRun(opts []RunOption) ValidationContext {
+	ctx := v.newContext()
+	for _, opt := range opts {
+		opt(&ctx)
+	}
+
+	return ctx
+}
+
+// validate validates value on ctx, the context of a new run.
+func (v *Validator) validate(ctx *ValidationContext, value any) error {
+	if !v.validateStruct(ctx, value) {
+		if ctx.scenario != "" {
+			return &UnknownScenarioError{Scenario: ctx.scenario, Type: reflect.TypeOf(value)}
+		}
+
+		return SystemErrorf("no type handler or validate tags for %T", value)
+	}
+
+	return v.finish(ctx, value)
+}
@@ -0,0 +1,100 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type runTeam struct {
+	Name    string `validate:"notEmpty"`
+	Members int    `validate:"lessThan={maxTeamSize}"`
+}
+
+type runTeamKey struct{}
+
+func TestRunOptionsCombine(t *testing.T) {
+	v := New(WithCollectAll())
+	v.AddTranslation("fr", "notEmpty", "ne doit pas être vide")
+	RegisterRuleCtx(v, "tenantActive", func(goctx context.Context, params []any) error {
+		if goctx.Value(runTeamKey{}) != "acme" {
+			return errors.New("unknown tenant")
+		}
+		return nil
+	})
+	RegisterType(v, func(team runTeam, ctx *ValidationContext) {
+		ctx.OnScenario("create").Field("Name").Check("tenantActive", team.Name).End()
+	})
+
+	goctx := context.WithValue(context.Background(), runTeamKey{}, "acme")
+	err := v.ValidateStruct(runTeam{Members: 30},
+		WithContext(goctx),
+		WithParams(map[string]any{"maxTeamSize": 25}),
+		WithLocale("fr"),
+		WithScenario("create"),
+	)
+
+	got := AsValidationErrors(err)
+	want := ValidationErrors{
+		{Field: "Name", Rule: "notEmpty", Message: "ne doit pas être vide"},
+		{Field: "Members", Rule: "lessThan"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %d failures", got, len(want))
+	}
+	for i := range want {
+		if got[i].Field != want[i].Field || got[i].Rule != want[i].Rule {
+			t.Errorf("failure %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+	if got[0].Message != want[0].Message {
+		t.Errorf("message = %q, want the fr translation %q", got[0].Message, want[0].Message)
+	}
+}
+
+func TestRunOptions(t *testing.T) {
+	v := New()
+	RegisterRuleCtx(v, "live", func(goctx context.Context, params []any) error { return nil })
+	RegisterType(v, func(team runTeam, ctx *ValidationContext) {
+		ctx.Field("Name").Check("live", team.Name)
+	})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		value   any
+		opts    []RunOption
+		wantErr error
+	}{
+		{"no options", sharedCity{City: "Oslo"}, nil, nil},
+		{"params", runTeam{Name: "a", Members: 3}, []RunOption{WithParams(map[string]any{"maxTeamSize": 5})}, nil},
+		{"cancelled context", runTeam{Name: "a"}, []RunOption{WithContext(cancelled)}, context.Canceled},
+		{"unknown scenario", 42, []RunOption{WithScenario("create")}, &UnknownScenarioError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateStruct(tt.value, tt.opts...)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Errorf("got %v, want nil", err)
+				}
+			case *UnknownScenarioError:
+				if !errors.As(err, &want) {
+					t.Errorf("got %v, want an UnknownScenarioError", err)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Errorf("got %v, want %v", err, want)
+				}
+			}
+
+			if result := v.ValidateAll(tt.value, tt.opts...); result.Empty() != (tt.wantErr == nil) {
+				t.Errorf("ValidateAll() = %+v, want empty %v", result, tt.wantErr == nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package validator
+
+import (
+	"reflect"
+	"slices"
+)
+
+type scenarioKey struct {
+	typ      reflect.Type
+	scenario string
+}
+
+// UnknownScenarioError is returned when validating in a scenario a value with
+// no handler for the scenario and nothing to fall back to.
+type UnknownScenarioError struct {
+	Scenario string
+	Type     reflect.Type
+}
+
+func (e *UnknownScenarioError) Error() string {
+	return "no handler for scenario " + e.Scenario + " of " + typeName(e.Type) + " and no default to fall back to"
+}
+
+// RegisterTypeScenario registers the handler for values of type T validated
+// with ValidateScenario in scenario, such as "create" or "update". It takes
+// the place of the handler registered with RegisterType for that scenario.
+func RegisterTypeScenario[T any](v *Validator, scenario string, handler func(s T, ctx *ValidationContext)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.scenarios[scenarioKey{typ: reflect.TypeFor[T](), scenario: scenario}] = func(a any, cc *ValidationContext) {
+		handler(a.(T), cc)
+	}
+}
+
+// ValidateScenario validates value like ValidateStruct in scenario. Values,
+// nested ones included, use the handler registered for the scenario and
+// fall back to their default handler. Tags apply in every scenario. It is
+// short for ValidateStruct with WithScenario.
+func (v *Validator) ValidateScenario(value any, scenario string) error {
+	return v.ValidateStruct(value, WithScenario(scenario))
+}
+
+// Scenario returns the scenario passed to ValidateScenario or WithScenario,
+// or "" when validating without one.
+func (ctx *ValidationContext) Scenario() string {
+	return ctx.scenario
+}
+
+// OnScenario is When for the active scenario: the checks that follow it, up
+// to the matching End, only run in one of scenarios.
+func (ctx *ValidationContext) OnScenario(scenarios ...string) *ValidationContext {
+	return ctx.When(slices.Contains(scenarios, ctx.scenario))
+}
+
+// scenarioHandler returns the handler for typ in scenario, from v or its
+// parents, falling back to its default handler.
+func (v *Validator) scenarioHandler(typ reflect.Type, scenario string) (HandlerFunc, bool) {
+	for w := v; w != nil && scenario != ""; w = w.parent {
+		w.mu.RLock()
+		handler, ok := w.scenarios[scenarioKey{typ: typ, scenario: scenario}]
+		w.mu.RUnlock()
+
+		if ok {
+			return handler, true
+		}
+	}
+
+	return v.handler(typ)
+}
+
+func typeName(typ reflect.Type) string {
+	if typ == nil {
+		return "nil"
+	}
+
+	return typ.String()
+}
@@ -0,0 +1,94 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type scenarioUser struct {
+	ID    string
+	Email string `validate:"isEmail"`
+	Owner scenarioOwner
+}
+
+type scenarioOwner struct {
+	Name string
+}
+
+type scenarioUntagged struct {
+	Name string
+}
+
+func TestValidateScenario(t *testing.T) {
+	v := New(WithCollectAll())
+	// Owner is validated through the tags, with the handler for the scenario
+	RegisterType(v, func(u scenarioUser, ctx *ValidationContext) {})
+	RegisterTypeScenario(v, "update", func(u scenarioUser, ctx *ValidationContext) {
+		ctx.Field("ID").Check("notEmpty", u.ID)
+	})
+	RegisterType(v, func(o scenarioOwner, ctx *ValidationContext) {
+		ctx.Field("Name").Check("notEmpty", o.Name)
+	})
+	RegisterTypeScenario(v, "update", func(o scenarioOwner, ctx *ValidationContext) {
+		ctx.Field("Name").Check("minLength", o.Name, 3)
+	})
+
+	tests := []struct {
+		name        string
+		scenario    string
+		value       any
+		wantFields  []string
+		wantUnknown bool
+	}{
+		{"default handler", "", scenarioUser{Email: "x"}, []string{"Email", "Owner.Name"}, false},
+		{"scenario handler", "update", scenarioUser{Email: "ada@example.com", Owner: scenarioOwner{Name: "Al"}}, []string{"ID", "Owner.Name"}, false},
+		{"scenario handler passes", "update", scenarioUser{ID: "1", Email: "ada@example.com", Owner: scenarioOwner{Name: "Ada"}}, nil, false},
+		{"scenario without handler falls back", "create", scenarioUser{Email: "ada@example.com"}, []string{"Owner.Name"}, false},
+		{"tags apply in every scenario", "update", scenarioUser{ID: "1", Email: "x", Owner: scenarioOwner{Name: "Ada"}}, []string{"Email"}, false},
+		{"unknown scenario without fallback", "update", scenarioUntagged{}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateScenario(tt.value, tt.scenario)
+
+			var unknown *UnknownScenarioError
+			if got := errors.As(err, &unknown); got != tt.wantUnknown {
+				t.Fatalf("got %v, want an UnknownScenarioError %v", err, tt.wantUnknown)
+			}
+			if tt.wantUnknown {
+				if unknown.Scenario != tt.scenario || Classify(err) != SystemError {
+					t.Errorf("got %+v classified as %v", unknown, Classify(err))
+				}
+				return
+			}
+
+			if got := failedFields(err); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("failed fields = %v, want %v (err %v)", got, tt.wantFields, err)
+			}
+		})
+	}
+}
+
+func TestScenarioOnContext(t *testing.T) {
+	v := New()
+	var seen []string
+	RegisterType(v, func(o scenarioOwner, ctx *ValidationContext) {
+		seen = append(seen, ctx.Scenario())
+		ctx.OnScenario("create").Field("Name").Check("notEmpty", o.Name).End()
+	})
+
+	if err := v.ValidateScenario(scenarioOwner{}, "update"); err != nil {
+		t.Errorf("OnScenario(create) ran in update: %v", err)
+	}
+	if err := v.ValidateScenario(scenarioOwner{}, "create"); err == nil {
+		t.Error("OnScenario(create) did not run in create")
+	}
+	if err := v.ValidateStruct(scenarioOwner{}); err != nil {
+		t.Errorf("OnScenario(create) ran without a scenario: %v", err)
+	}
+	if want := []string{"update", "create", ""}; !slices.Equal(seen, want) {
+		t.Errorf("Scenario() = %q, want %q", seen, want)
+	}
+}
@@ -0,0 +1,79 @@
+package validator
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// structSchema is what validation needs to know about a type from its
+// fields and tags. It depends only on the type, so it is worked out on first
+// use and shared by every validator.
+type structSchema struct {
+	tagged       bool
+	allFields    string
+	hasAllFields bool
+	groups       []string
+	groupsErr    error
+	fields       []fieldSchema
+}
+
+// fieldSchema describes an exported field of a struct and its parsed
+// validate tag.
+type fieldSchema struct {
+	index     int
+	field     reflect.StructField
+	rules     []tagRule
+	err       error
+	sensitive bool
+	groups    []string
+}
+
+var schemas sync.Map // reflect.Type -> *structSchema
+
+// schemaFor returns the schema of typ, parsing its tags on first use.
+func schemaFor(typ reflect.Type) *structSchema {
+	if typ == nil {
+		return &structSchema{}
+	}
+
+	if s, ok := schemas.Load(typ); ok {
+		return s.(*structSchema)
+	}
+
+	s := &structSchema{tagged: hasValidateTags(typ)}
+	s.allFields, s.hasAllFields = structDirective(typ, "allFields")
+	if typ.Kind() == reflect.Struct {
+		s.groups, s.groupsErr = directiveGroups(typ)
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() || field.Name == "_" {
+				continue
+			}
+
+			rules, err := parseTag(field.Tag.Get("validate"))
+			if err == nil {
+				if err = diveError(field.Type, rules); err != nil {
+					rules = nil
+				}
+			}
+			// rules are shared, so appending the field value must not write
+			// into their backing arrays
+			for j := range rules {
+				rules[j].params = slices.Clip(rules[j].params)
+			}
+
+			s.fields = append(s.fields, fieldSchema{
+				index:     i,
+				field:     field,
+				rules:     rules,
+				err:       err,
+				sensitive: field.Tag.Get("sensitive") == "true",
+				groups:    fieldGroups(field),
+			})
+		}
+	}
+
+	actual, _ := schemas.LoadOrStore(typ, s)
+	return actual.(*structSchema)
+}
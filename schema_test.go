@@ -0,0 +1,121 @@
+package validator
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNilValueIsNotValidatable(t *testing.T) {
+	v := New()
+
+	tests := []struct {
+		name   string
+		failed func() bool
+	}{
+		{"ValidateStruct", func() bool { return v.ValidateStruct(nil) != nil }},
+		{"Explain", func() bool { _, err := v.Explain(nil); return err != nil }},
+		{"ValidateAll", func() bool { return v.ValidateAll(nil).HTTPStatus() == http.StatusInternalServerError }},
+		{"ctx.Validate", func() bool { ctx := v.newContext(); return ctx.Validate(nil).Err() != nil }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.failed() {
+				t.Fatal("validating nil did not fail")
+			}
+		})
+	}
+}
+
+// clipTagged's quoted arguments are appended one by one while parsing, which
+// leaves spare capacity in the params unless they are clipped.
+type clipTagged struct {
+	Code string `validate:"remember='a','b','c'"`
+}
+
+func TestCachedParamsAreClipped(t *testing.T) {
+	for _, f := range schemaFor(reflect.TypeFor[clipTagged]()).fields {
+		for _, rule := range f.rules {
+			if cap(rule.params) != len(rule.params) {
+				t.Errorf("%s params have spare capacity %d", rule.name, cap(rule.params)-len(rule.params))
+			}
+		}
+	}
+
+	v := New()
+	var seen [][]any
+	RegisterRule(v, "remember", func(params []any) error {
+		seen = append(seen, params)
+		return nil
+	})
+
+	for _, code := range []string{"x", "y", "z"} {
+		if err := v.ValidateStruct(clipTagged{Code: code}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	want := [][]any{{"a", "b", "c", "x"}, {"a", "b", "c", "y"}, {"a", "b", "c", "z"}}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("rule saw %v, want %v", seen, want)
+	}
+}
+
+type benchSignup struct {
+	Name  string `validate:"minLength=2,maxLength=64"`
+	Email string `validate:"isEmail"`
+	Age   int    `validate:"greaterThan=17"`
+	Tags  []string
+}
+
+func TestSchemaIsCached(t *testing.T) {
+	typ := reflect.TypeFor[benchSignup]()
+	first := schemaFor(typ)
+	if second := schemaFor(typ); second != first {
+		t.Error("schemaFor parsed the tags again")
+	}
+
+	if got := len(first.fields); got != 4 {
+		t.Errorf("schema has %d fields, want 4", got)
+	}
+}
+
+func BenchmarkCheckGreaterThan(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := Check("greaterThan", 17, 42).Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	value := benchSignup{Name: "Ada", Email: "ada@example.com", Age: 36}
+
+	v := New()
+	RegisterType(v, func(s benchSignup, ctx *ValidationContext) {
+		ctx.Field("Name").Check("minLength", s.Name, 2).Check("maxLength", s.Name, 64)
+		ctx.Field("Email").Check("isEmail", s.Email)
+		ctx.Field("Age").Check("greaterThan", 17, s.Age)
+	})
+
+	b.Run("handler", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := Validate(v, value); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("tags", func(b *testing.B) {
+		v := New()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := v.ValidateStruct(value); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,644 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fieldRefRules take the name of a sibling field as their first tag argument.
+// The sibling's value is passed to the rule right after its name.
+var fieldRefRules = map[string]bool{
+	"afterField":      true,
+	"beforeField":     true,
+	"eqField":         true,
+	"multipleOfField": true,
+	"neField":         true,
+}
+
+// subjectFirstRules take the value being validated as their first parameter
+// rather than their last, so tags pass the field value ahead of the arguments.
+var subjectFirstRules = map[string]bool{
+	"coversEnum":  true,
+	"exactLength": true,
+	"mapValues":   true,
+	"matchesHash": true,
+	"maxLength":   true,
+	"minLength":   true,
+	"notOneOf":    true,
+	"oneOf":       true,
+	"regex":       true,
+}
+
+// withSubject returns params with the value being validated added in the
+// place ruleName expects it.
+func withSubject(ruleName string, subject any, params []any) []any {
+	if subjectFirstRules[ruleName] {
+		return append([]any{subject}, params...)
+	}
+
+	return append(params[:len(params):len(params)], subject)
+}
+
+type tagRule struct {
+	name   string
+	params []any
+}
+
+// parseTag splits a validate tag such as "notEmpty,greaterThan=17" into rules.
+// Multiple arguments are separated by colons and {name} refers to a ParamRef.
+// Numeric arguments are converted to int or float64 so comparison rules
+// compare numerically. Arguments wrapped in single quotes are taken literally
+// and may contain commas and colons; a quoted argument following a rule,
+// as in oneOf='a,b','c,d', is another argument to that rule.
+func parseTag(tag string) ([]tagRule, error) {
+	parts, err := splitTag(tag, ',')
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []tagRule
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		if strings.HasPrefix(part, "'") && len(rules) > 0 {
+			args, err := parseTagArgs(part)
+			if err != nil {
+				return nil, err
+			}
+
+			last := &rules[len(rules)-1]
+			last.params = append(last.params, args...)
+			continue
+		}
+
+		name, args, hasArgs := strings.Cut(part, "=")
+		rule := tagRule{name: name}
+		if hasArgs {
+			rule.params, err = parseTagArgs(args)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+func parseTagArgs(args string) ([]any, error) {
+	parts, err := splitTag(args, ':')
+	if err != nil {
+		return nil, err
+	}
+
+	params := make([]any, 0, len(parts))
+	for _, arg := range parts {
+		if len(arg) >= 2 && strings.HasPrefix(arg, "'") && strings.HasSuffix(arg, "'") {
+			params = append(params, arg[1:len(arg)-1])
+			continue
+		}
+
+		params = append(params, parseTagParam(arg))
+	}
+
+	return params, nil
+}
+
+// splitTag splits s on sep, ignoring separators inside single quotes. The
+// quotes are kept so callers can tell quoted arguments apart.
+func splitTag(s string, sep byte) ([]string, error) {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\'':
+			quoted = !quoted
+		case s[i] == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", s)
+	}
+
+	return append(parts, s[start:]), nil
+}
+
+func parseTagParam(arg string) any {
+	if strings.HasPrefix(arg, "{") && strings.HasSuffix(arg, "}") {
+		return Param(arg[1 : len(arg)-1])
+	}
+
+	if i, err := strconv.Atoi(arg); err == nil {
+		return i
+	}
+
+	if isDecimal(arg) {
+		if f, err := strconv.ParseFloat(arg, 64); err == nil {
+			return f
+		}
+	}
+
+	return arg
+}
+
+// isDecimal reports whether arg is written as a decimal number, so words
+// such as "nan" and "inf" and hex floats that ParseFloat also accepts stay
+// strings.
+func isDecimal(arg string) bool {
+	return strings.ContainsAny(arg, "0123456789") &&
+		strings.Trim(arg, "0123456789+-.eE") == ""
+}
+
+// ValidateStruct validates s using its registered handler, its struct-level
+// directives and the validate tags on its exported fields. When a type has
+// both a handler and tags, the handler runs first and the tags run after it
+// on the same context. opts configure this run only.
+func (v *Validator) ValidateStruct(s any, opts ...RunOption) error {
+	ctx := v.newRun(opts)
+	return v.validate(&ctx, s)
+}
+
+func hasValidateTags(typ reflect.Type) bool {
+	return hasValidateTagsSeen(typ, map[reflect.Type]bool{})
+}
+
+func hasValidateTagsSeen(typ reflect.Type, seen map[reflect.Type]bool) bool {
+	typ = containedType(typ)
+	if typ == nil || typ.Kind() != reflect.Struct || seen[typ] {
+		return false
+	}
+	seen[typ] = true
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Name == "_" {
+			continue
+		}
+
+		if field.Tag.Get("validate") != "" || hasValidateTagsSeen(field.Type, seen) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// containedType strips pointers and containers down to the element type.
+func containedType(typ reflect.Type) reflect.Type {
+	for typ != nil {
+		switch typ.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array, reflect.Map:
+			typ = typ.Elem()
+		default:
+			return typ
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) checkTags(ctx *ValidationContext, rv reflect.Value) {
+	schema := schemaFor(rv.Type())
+	if schema.groupsErr != nil {
+		ctx.tagError(schema.groupsErr)
+	}
+
+	for i := 0; i < len(schema.fields) && ctx.fatal == nil; i++ {
+		f := &schema.fields[i]
+		if inGroups(f.groups, schema.groups) {
+			continue
+		}
+
+		if v.concurrent {
+			prefix := ctx.prefix
+			ctx.Group(func(g *ValidationContext) {
+				g.prefix, g.field = prefix, prefix
+				v.checkField(g, rv, f)
+			})
+			continue
+		}
+
+		v.checkField(ctx, rv, f)
+	}
+	ctx.Wait()
+
+	if len(schema.groups) > 0 && !ctx.skip() {
+		v.checkGroups(ctx, rv, schema)
+	}
+}
+
+// checkField checks the validate tag of the field f of the struct rv.
+func (v *Validator) checkField(ctx *ValidationContext, rv reflect.Value, f *fieldSchema) {
+	fv := rv.Field(f.index)
+	prefix, current := ctx.prefix, ctx.field
+	if f.field.Anonymous {
+		ctx.Field("")
+	} else {
+		ctx.Field(v.fieldName(f.field))
+	}
+	if f.sensitive {
+		ctx.Sensitive()
+	}
+
+	if f.err != nil {
+		ctx.tagError(fmt.Errorf("validate tag on %v.%s: %w", rv.Type(), f.field.Name, f.err))
+	}
+
+	v.applyTagRules(ctx, rv, f.field.Name, fv, f.rules)
+	ctx.prefix, ctx.field = prefix, current
+}
+
+// directiveGroups returns the groups named by a oneOfGroups directive, e.g.
+//
+//	_ struct{} `validate:"oneOfGroups=email:phone"`
+//
+// Fields join groups with a comma-separated group tag. A directive without
+// groups, with an empty group name or naming a group no field joins is an
+// error, since such a group would always pass.
+func directiveGroups(typ reflect.Type) ([]string, error) {
+	directive, ok := structDirective(typ, "oneOfGroups")
+	if !ok {
+		return nil, nil
+	}
+
+	if directive == "" {
+		return nil, fmt.Errorf("oneOfGroups directive on %v names no groups", typ)
+	}
+
+	groups := strings.Split(directive, ":")
+	for _, group := range groups {
+		joined := false
+		for i := 0; i < typ.NumField() && !joined; i++ {
+			joined = typ.Field(i).IsExported() && slices.Contains(fieldGroups(typ.Field(i)), group)
+		}
+
+		if !joined {
+			return nil, fmt.Errorf("oneOfGroups directive on %v: no field is in group %q", typ, group)
+		}
+	}
+
+	return groups, nil
+}
+
+// fieldGroups returns the groups listed in the group tag of field.
+func fieldGroups(field reflect.StructField) []string {
+	tag := field.Tag.Get("group")
+	if tag == "" {
+		return nil
+	}
+
+	return strings.Split(tag, ",")
+}
+
+// inGroups reports whether any of fieldGroups is one of groups.
+func inGroups(fieldGroups []string, groups []string) bool {
+	for _, g := range fieldGroups {
+		if slices.Contains(groups, g) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkGroups passes when the fields of at least one group are all valid.
+// When none passes, the failures of every group are recorded, or only the
+// first one when ctx stops at the first failure.
+func (v *Validator) checkGroups(ctx *ValidationContext, rv reflect.Value, schema *structSchema) {
+	children := make([]ValidationContext, 0, len(schema.groups))
+	for _, group := range schema.groups {
+		child := ctx.child()
+		child.prefix, child.field = ctx.prefix, ctx.prefix
+
+		for i := 0; i < len(schema.fields) && !child.skip(); i++ {
+			if f := &schema.fields[i]; slices.Contains(f.groups, group) {
+				v.checkField(&child, rv, f)
+			}
+		}
+
+		if child.fatal == nil && len(child.Errors()) == 0 && ctx.plan == nil {
+			ctx.warnings = append(ctx.warnings, child.warnings...)
+			return
+		}
+		children = append(children, child)
+	}
+
+	for i := 0; i < len(children) && !ctx.skip(); i++ {
+		ctx.merge(&children[i])
+	}
+}
+
+// WithJSONTagNames reports failures in struct fields under the name in their
+// json tag, so that field paths match the wire format. Fields without a json
+// name, or with json:"-", keep their Go name.
+func WithJSONTagNames() Option {
+	return func(cfg *Config) {
+		cfg.UseJSONTagNames = true
+	}
+}
+
+// WithConcurrentFields validates the tagged fields of each struct in
+// parallel, for structs with several slow checks such as context-aware rules
+// that do I/O. Failures are still reported in field order. Rules, handlers
+// and middleware must then be safe for concurrent use, and each field sees
+// its own copy of the memo.
+func WithConcurrentFields() Option {
+	return func(cfg *Config) {
+		cfg.ConcurrentFields = true
+	}
+}
+
+// fieldName is the name field is reported under.
+func (v *Validator) fieldName(field reflect.StructField) string {
+	if !v.jsonTagNames {
+		return field.Name
+	}
+
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+
+	return name
+}
+
+// applyTagRules checks fv, a field of the struct rv or an element of one,
+// against rules. The rules after a dive apply to each element of fv, and
+// optional skips the remaining rules when fv is the zero value.
+func (v *Validator) applyTagRules(ctx *ValidationContext, rv reflect.Value, name string, fv reflect.Value, rules []tagRule) {
+	typ := rv.Type()
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			v.checkElements(ctx, rv, name, fv, rules[i+1:])
+			return
+		}
+
+		if rule.name == "optional" {
+			if isAbsent(fv) {
+				return
+			}
+			continue
+		}
+
+		if _, ok := v.rule(rule.name); !ok {
+			ctx.tagError(fmt.Errorf("validate tag on %v.%s: %w", typ, name, &UnknownRuleError{Name: rule.name}))
+			continue
+		}
+
+		params := rule.params
+		if fieldRefRules[rule.name] {
+			sibling, err := siblingField(rv, rule)
+			if err != nil {
+				ctx.tagError(fmt.Errorf("validate tag on %v.%s: %w", typ, name, err))
+				continue
+			}
+
+			params = append(params[:1:1], sibling.Interface())
+		}
+
+		ctx.Check(rule.name, withSubject(rule.name, fv.Interface(), params)...)
+	}
+
+	v.checkNested(ctx, fv)
+}
+
+// splitKeyRules separates the rules between keys and endkeys, which apply to
+// map keys, from the rules after them, which apply to map values.
+func splitKeyRules(rules []tagRule) (keyRules []tagRule, valueRules []tagRule, err error) {
+	if len(rules) == 0 || rules[0].name != "keys" {
+		return nil, rules, nil
+	}
+
+	for i, rule := range rules {
+		if rule.name == "endkeys" {
+			return rules[1:i], rules[i+1:], nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("keys without endkeys")
+}
+
+// diveError reports a dive in rules that cannot apply to a value of type
+// ftype, following dives into element and key types.
+func diveError(ftype reflect.Type, rules []tagRule) error {
+	i := slices.IndexFunc(rules, func(rule tagRule) bool { return rule.name == "dive" })
+	if i < 0 {
+		return nil
+	}
+
+	switch ftype.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+	default:
+		return fmt.Errorf("dive requires a slice, array or map, got %v", ftype)
+	}
+
+	keyRules, valueRules, err := splitKeyRules(rules[i+1:])
+	if err != nil {
+		return err
+	}
+
+	if keyRules != nil {
+		if ftype.Kind() != reflect.Map {
+			return fmt.Errorf("keys requires a map, got %v", ftype)
+		}
+		if err := diveError(ftype.Key(), keyRules); err != nil {
+			return err
+		}
+	}
+
+	return diveError(ftype.Elem(), valueRules)
+}
+
+// siblingField resolves the field named by the first argument of a
+// fieldRefRules rule.
+func siblingField(rv reflect.Value, rule tagRule) (reflect.Value, error) {
+	if len(rule.params) != 1 {
+		return reflect.Value{}, fmt.Errorf("%s expects a single field name, got %d arguments", rule.name, len(rule.params))
+	}
+
+	name, ok := rule.params[0].(string)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("%s expects a field name, got %v", rule.name, rule.params[0])
+	}
+
+	return fieldByName(rv, name)
+}
+
+// checkElements validates every element of a slice, array or map field
+// against rules, qualifying the field path with the index or key, e.g.
+// Items[2].Quantity. Failures of the rules between keys and endkeys are
+// reported against the key itself, e.g. Scores[alice](key). Elements that
+// are structs are validated as well.
+func (v *Validator) checkElements(ctx *ValidationContext, rv reflect.Value, name string, fv reflect.Value, rules []tagRule) {
+	typ := rv.Type()
+	keyRules, valueRules, err := splitKeyRules(rules)
+	if err != nil {
+		ctx.tagError(fmt.Errorf("validate tag on %v.%s: %w", typ, name, err))
+		return
+	}
+
+	prefix, field := ctx.prefix, ctx.field
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if keyRules != nil {
+			ctx.tagError(fmt.Errorf("validate tag on %v.%s: keys requires a map, got %v", typ, name, fv.Type()))
+			break
+		}
+
+		for i := 0; i < fv.Len() && !ctx.skip(); i++ {
+			ctx.field = fmt.Sprintf("%s[%d]", field, i)
+			v.applyTagRules(ctx, rv, name, fv.Index(i), valueRules)
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(fv) {
+			if ctx.skip() {
+				break
+			}
+
+			if keyRules != nil {
+				ctx.field = fmt.Sprintf("%s[%v](key)", field, key)
+				v.applyTagRules(ctx, rv, name, key, keyRules)
+				if ctx.skip() {
+					break
+				}
+			}
+			ctx.field = fmt.Sprintf("%s[%v]", field, key)
+			v.applyTagRules(ctx, rv, name, fv.MapIndex(key), valueRules)
+		}
+	default:
+		ctx.tagError(fmt.Errorf("validate tag on %v.%s: dive requires a slice, array or map, got %v", typ, name, fv.Type()))
+	}
+	ctx.prefix, ctx.field = prefix, field
+}
+
+// checkNested descends into struct values, non-nil pointers to structs and
+// non-nil interfaces, which are validated by their dynamic type. Pointers are
+// tracked while they are being validated, so cycles in self-referential data
+// are cut while a value shared by several fields is validated for each.
+func (v *Validator) checkNested(ctx *ValidationContext, fv reflect.Value) {
+	for fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface {
+		if fv.IsNil() {
+			return
+		}
+
+		if fv.Kind() == reflect.Pointer {
+			if !ctx.visit(fv) {
+				return
+			}
+			defer ctx.leave(fv)
+		}
+		fv = fv.Elem()
+	}
+
+	if fv.Kind() != reflect.Struct {
+		if _, ok := v.handler(fv.Type()); !ok {
+			return
+		}
+	}
+
+	ctx.prefix = ctx.field
+	v.validateStruct(ctx, fv.Interface())
+}
+
+// sortedKeys returns the keys of a map in a stable order so that errors for
+// map elements are reported deterministically.
+func sortedKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+
+	return keys
+}
+
+// CheckStructTags reports configuration mistakes in the validate tags of
+// sample's type, such as unknown rules or missing arguments, without running
+// any validation.
+func (v *Validator) CheckStructTags(sample any) []error {
+	return v.checkStructTags(reflect.TypeOf(sample), map[reflect.Type]bool{})
+}
+
+func (v *Validator) checkStructTags(typ reflect.Type, seen map[reflect.Type]bool) []error {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct || seen[typ] {
+		return nil
+	}
+	seen[typ] = true
+
+	var errs []error
+	if _, err := directiveGroups(typ); err != nil {
+		errs = append(errs, err)
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Name == "_" {
+			continue
+		}
+
+		rules, err := parseTag(field.Tag.Get("validate"))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("validate tag on %v.%s: %w", typ, field.Name, err))
+		}
+
+		errs = append(errs, v.checkTagRules(typ, field.Name, field.Type, rules)...)
+		errs = append(errs, v.checkStructTags(containedType(field.Type), seen)...)
+	}
+
+	return errs
+}
+
+// checkTagRules reports configuration mistakes in rules applied to a value of
+// type ftype, following dives into element and key types.
+func (v *Validator) checkTagRules(typ reflect.Type, name string, ftype reflect.Type, rules []tagRule) []error {
+	var errs []error
+	for i, rule := range rules {
+		if rule.name == "dive" {
+			if err := diveError(ftype, rules[i:]); err != nil {
+				return append(errs, fmt.Errorf("validate tag on %v.%s: %w", typ, name, err))
+			}
+
+			keyRules, valueRules, _ := splitKeyRules(rules[i+1:])
+			if keyRules != nil {
+				errs = append(errs, v.checkTagRules(typ, name, ftype.Key(), keyRules)...)
+			}
+
+			return append(errs, v.checkTagRules(typ, name, ftype.Elem(), valueRules)...)
+		}
+
+		if rule.name == "optional" {
+			continue
+		}
+
+		n := len(rule.params) + 1
+		if fieldRefRules[rule.name] {
+			if _, err := siblingField(reflect.New(typ).Elem(), rule); err != nil {
+				errs = append(errs, fmt.Errorf("validate tag on %v.%s: %w", typ, name, err))
+				continue
+			}
+			n++
+		}
+
+		// the field value is passed to the rule after the tag arguments
+		if err := v.CheckArity(rule.name, n); err != nil {
+			errs = append(errs, fmt.Errorf("validate tag on %v.%s: %w", typ, name, err))
+		}
+	}
+
+	return errs
+}
@@ -0,0 +1,539 @@
+package validator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failedFields returns the field paths of the failures in err.
+func failedFields(err error) []string {
+	var fields []string
+	for _, e := range flattenErrors(err) {
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			fields = append(fields, fe.Field)
+		}
+	}
+
+	return fields
+}
+
+type sharedCity struct {
+	City string `validate:"notEmpty"`
+}
+
+type sharedPointers struct {
+	C *sharedCity
+	D *sharedCity
+}
+
+type cyclicNode struct {
+	Name string `validate:"notEmpty"`
+	Next *cyclicNode
+}
+
+func TestNestedPointers(t *testing.T) {
+	addr := &sharedCity{}
+	loop := &cyclicNode{Name: "a"}
+	loop.Next = &cyclicNode{Next: loop}
+
+	tests := []struct {
+		name  string
+		value any
+		want  []string
+	}{
+		{"shared pointer", sharedPointers{C: addr, D: addr}, []string{"C.City", "D.City"}},
+		{"cycle", loop, []string{"Next.Name"}},
+	}
+
+	for _, tt := range tests {
+		for _, opts := range [][]Option{{WithCollectAll()}, {WithCollectAll(), WithConcurrentFields()}} {
+			t.Run(tt.name, func(t *testing.T) {
+				got := failedFields(New(opts...).ValidateStruct(tt.value))
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("failed fields = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+type diveScores struct {
+	Emails []string         `validate:"notEmpty,dive,isEmail"`
+	Scores map[string]int   `validate:"dive,keys,minLength=3,endkeys,greaterThan=0"`
+	Grid   [][]int          `validate:"dive,dive,greaterThan=0"`
+	Tags   map[string][]int `validate:"dive,notEmpty"`
+}
+
+func TestDive(t *testing.T) {
+	valid := diveScores{Emails: []string{"a@example.com"}}
+
+	tests := []struct {
+		name   string
+		mutate func(d *diveScores)
+		want   []string
+	}{
+		{"valid", func(d *diveScores) {}, nil},
+		{"empty slice", func(d *diveScores) { d.Emails = nil }, []string{"Emails"}},
+		{"element", func(d *diveScores) { d.Emails = append(d.Emails, "nope") }, []string{"Emails[1]"}},
+		{"map value", func(d *diveScores) { d.Scores = map[string]int{"alice": 0} }, []string{"Scores[alice]"}},
+		{"map key", func(d *diveScores) { d.Scores = map[string]int{"al": 1} }, []string{"Scores[al](key)"}},
+		{"map key and value", func(d *diveScores) { d.Scores = map[string]int{"al": 0} }, []string{"Scores[al](key)", "Scores[al]"}},
+		{"nested dive", func(d *diveScores) { d.Grid = [][]int{{1, 2}, {3, 0}} }, []string{"Grid[1][1]"}},
+		{"map of slices", func(d *diveScores) { d.Tags = map[string][]int{"x": nil} }, []string{"Tags[x]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := valid
+			tt.mutate(&value)
+			got := failedFields(New(WithCollectAll()).ValidateStruct(value))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type diveScalar struct {
+	Name  string `validate:"notEmpty,dive,minLength=1"`
+	Other string `validate:"notEmpty"`
+}
+
+type diveKeysOnSlice struct {
+	Names []string `validate:"dive,keys,notEmpty,endkeys"`
+}
+
+type diveTooDeep struct {
+	Names []string `validate:"dive,dive,notEmpty"`
+}
+
+func TestDiveOnNonContainer(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+	}{
+		{"scalar", diveScalar{Name: "x", Other: "y"}},
+		{"keys on slice", diveKeysOnSlice{Names: []string{"x"}}},
+		{"too deep", diveTooDeep{Names: []string{"x"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ := reflect.TypeOf(tt.value)
+			if err := schemaFor(typ).fields[0].err; err == nil {
+				t.Errorf("schemaFor(%v) accepted the dive", typ)
+			}
+
+			if err := New().ValidateStruct(tt.value); err == nil || Classify(err) != SystemError {
+				t.Errorf("ValidateStruct() = %v, want a system error", err)
+			}
+			if _, err := New().Explain(tt.value); err == nil {
+				t.Error("Explain() accepted the dive")
+			}
+			if errs := New().CheckStructTags(tt.value); len(errs) != 1 {
+				t.Errorf("CheckStructTags() = %v, want one error", errs)
+			}
+		})
+	}
+}
+
+type tagsValid struct {
+	Name   string   `validate:"notEmpty,maxLength=32"`
+	Age    int      `validate:"greaterThan=17"`
+	Emails []string `validate:"dive,isEmail"`
+	City   sharedCity
+}
+
+type tagsUnknownRule struct {
+	Name string `validate:"notEmpty,isFancy"`
+}
+
+type tagsMissingArg struct {
+	Age int `validate:"greaterThan"`
+}
+
+type tagsNestedBad struct {
+	Inner tagsUnknownRule
+	Other tagsMissingArg
+}
+
+func TestCheckStructTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  []string
+	}{
+		{"valid", tagsValid{}, nil},
+		{"pointer", &tagsValid{}, nil},
+		{"unknown rule", tagsUnknownRule{}, []string{"tagsUnknownRule.Name", "isFancy"}},
+		{"missing argument", tagsMissingArg{}, []string{"tagsMissingArg.Age", "greaterThan"}},
+		{"nested", tagsNestedBad{}, []string{"isFancy", "greaterThan"}},
+		{"unterminated quote", planBrokenTag{}, []string{"planBrokenTag.Age", "unterminated quote"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := New().CheckStructTags(tt.value)
+			if tt.want == nil {
+				if len(errs) > 0 {
+					t.Errorf("CheckStructTags() = %v", errs)
+				}
+				return
+			}
+
+			joined := errors.Join(errs...)
+			if joined == nil {
+				t.Fatal("CheckStructTags() found nothing")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(joined.Error(), want) {
+					t.Errorf("CheckStructTags() = %v, want a mention of %q", joined, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    []tagRule
+		wantErr bool
+	}{
+		{"empty", "", nil, false},
+		{"plain", "notEmpty,greaterThan=17", []tagRule{{"notEmpty", nil}, {"greaterThan", []any{17}}}, false},
+		{"colon arguments", "between=1:2.5", []tagRule{{"between", []any{1, 2.5}}}, false},
+		{"quoted commas", "oneOf='a,b','c,d'", []tagRule{{"oneOf", []any{"a,b", "c,d"}}}, false},
+		{"quoted colons", "regex='^a:b$',notEmpty", []tagRule{{"regex", []any{"^a:b$"}}, {"notEmpty", nil}}, false},
+		{"quoted number stays a string", "oneOf='17':18", []tagRule{{"oneOf", []any{"17", 18}}}, false},
+		{"exponent", "lessThan=1e3:-2.5E-1", []tagRule{{"lessThan", []any{1e3, -0.25}}}, false},
+		{"nan stays a string", "oneOf=open nan:NaN", []tagRule{{"oneOf", []any{"open nan", "NaN"}}}, false},
+		{"inf stays a string", "oneOf=inf:+Inf:-infinity", []tagRule{{"oneOf", []any{"inf", "+Inf", "-infinity"}}}, false},
+		{"hex float stays a string", "oneOf=0x1p-2", []tagRule{{"oneOf", []any{"0x1p-2"}}}, false},
+		{"overflow stays a string", "oneOf=1e400", []tagRule{{"oneOf", []any{"1e400"}}}, false},
+		{"not a number", "oneOf=1.2.3:e:-", []tagRule{{"oneOf", []any{"1.2.3", "e", "-"}}}, false},
+		{"param ref", "lessThan={max}", []tagRule{{"lessThan", []any{Param("max")}}}, false},
+		{"spaces", " notEmpty , isEmail ", []tagRule{{"notEmpty", nil}, {"isEmail", nil}}, false},
+		{"unterminated quote", "oneOf='a,b", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTag(tt.tag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseTag(%q) error = %v, wantErr %v", tt.tag, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTag(%q) = %#v, want %#v", tt.tag, got, tt.want)
+			}
+		})
+	}
+}
+
+type quotedTagged struct {
+	Pair string `validate:"oneOf='a,b','c,d'"`
+}
+
+func TestQuotedTagArguments(t *testing.T) {
+	v := New()
+	var got []any
+	RegisterRule(v, "oneOf", func(params []any) error {
+		got = params
+		return nil
+	})
+
+	if err := v.ValidateStruct(quotedTagged{Pair: "c,d"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := []any{"c,d", "a,b", "c,d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("oneOf got %q, want %q", got, want)
+	}
+
+	if err := New().ValidateStruct(quotedTagged{Pair: "a"}); err == nil {
+		t.Error("oneOf accepted a value outside the list")
+	}
+}
+
+type multipleOrder struct {
+	PackSize int     `validate:"greaterOrEqual=0"`
+	Quantity int     `validate:"multipleOfField=PackSize"`
+	Weight   float64 `validate:"multipleOfField=Step"`
+	Step     float64
+}
+
+type multipleMissingSibling struct {
+	Quantity int `validate:"multipleOfField=PackSize"`
+}
+
+func TestMultipleOfField(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		want      []string
+		wantClass Classification
+	}{
+		{"multiple", multipleOrder{PackSize: 6, Quantity: 18}, nil, UserError},
+		{"not a multiple", multipleOrder{PackSize: 6, Quantity: 20}, []string{"Quantity"}, UserError},
+		{"zero quantity", multipleOrder{PackSize: 6}, nil, UserError},
+		{"zero increment", multipleOrder{Quantity: 5}, []string{"Quantity"}, UserError},
+		{"zero of zero", multipleOrder{}, nil, UserError},
+		{"negative multiple", multipleOrder{PackSize: 6, Quantity: -12}, nil, UserError},
+		{"fractional", multipleOrder{Step: 0.25, Weight: 1.75}, nil, UserError},
+		{"fractional miss", multipleOrder{Step: 0.25, Weight: 1.8}, []string{"Weight"}, UserError},
+		{"missing sibling", multipleMissingSibling{Quantity: 1}, nil, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(WithCollectAll()).ValidateStruct(tt.value)
+			if got := failedFields(err); !slices.Equal(got, tt.want) {
+				t.Fatalf("failed fields = %v, want %v (%v)", got, tt.want, err)
+			}
+			if tt.wantClass == SystemError && err == nil {
+				t.Fatal("ValidateStruct() accepted a tag naming no field")
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%v classified as %v, want %v", err, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+type dynamicCircle struct {
+	Radius int
+}
+
+type dynamicSquare struct {
+	Side int `validate:"greaterThan=0"`
+}
+
+type dynamicDrawing struct {
+	Title string `validate:"notEmpty"`
+	Shape any
+}
+
+func TestInterfaceFieldDynamicType(t *testing.T) {
+	v := New(WithCollectAll())
+	RegisterType(v, func(c dynamicCircle, ctx *ValidationContext) {
+		ctx.Field("Radius").Check("greaterThan", 0, c.Radius)
+	})
+
+	tests := []struct {
+		name  string
+		value dynamicDrawing
+		want  []string
+	}{
+		{"nil interface", dynamicDrawing{Title: "t"}, nil},
+		{"valid handler type", dynamicDrawing{Title: "t", Shape: dynamicCircle{Radius: 1}}, nil},
+		{"invalid handler type", dynamicDrawing{Title: "t", Shape: dynamicCircle{}}, []string{"Shape.Radius"}},
+		{"valid tagged type", dynamicDrawing{Title: "t", Shape: dynamicSquare{Side: 2}}, nil},
+		{"invalid tagged type", dynamicDrawing{Title: "t", Shape: dynamicSquare{}}, []string{"Shape.Side"}},
+		{"pointer to type", dynamicDrawing{Title: "t", Shape: &dynamicCircle{}}, []string{"Shape.Radius"}},
+		{"nil pointer in interface", dynamicDrawing{Title: "t", Shape: (*dynamicCircle)(nil)}, nil},
+		{"type without rules", dynamicDrawing{Shape: 42}, []string{"Title"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateStruct(tt.value)
+			if got := failedFields(err); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+type jsonNamedAddress struct {
+	City string `json:"city" validate:"notEmpty"`
+}
+
+type jsonNamedUser struct {
+	Email   string            `json:"email_address,omitempty" validate:"isEmail"`
+	Name    string            `json:",omitempty" validate:"notEmpty"`
+	Secret  string            `json:"-" validate:"notEmpty"`
+	Plain   string            `validate:"notEmpty"`
+	Address jsonNamedAddress  `json:"address"`
+	Tags    []string          `json:"tags" validate:"dive,notEmpty"`
+	Labels  map[string]string `json:"labels" validate:"dive,notEmpty"`
+}
+
+func TestJSONTagNames(t *testing.T) {
+	value := jsonNamedUser{Email: "nope", Tags: []string{""}, Labels: map[string]string{"k": ""}}
+
+	tests := []struct {
+		name    string
+		options []Option
+		want    []string
+	}{
+		{"go names", nil, []string{"Email", "Name", "Secret", "Plain", "Address.City", "Tags[0]", "Labels[k]"}},
+		{"json names", []Option{WithJSONTagNames()}, []string{"email_address", "Name", "Secret", "Plain", "address.city", "tags[0]", "labels[k]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(append(tt.options, WithCollectAll())...)
+			if got := failedFields(v.ValidateStruct(value)); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// lookupHosts has fields checked by the context-aware resolves rule, which
+// answers after the delay in its value's name and fails names starting with
+// "bad".
+type lookupHosts struct {
+	Primary   string `validate:"resolves"`
+	Secondary string `validate:"resolves"`
+	Backup    string `validate:"resolves"`
+	Mirror    string `validate:"resolves"`
+	Owner     struct {
+		Host string `validate:"resolves"`
+	}
+}
+
+type lookupKey struct{}
+
+func TestConcurrentFields(t *testing.T) {
+	var calls atomic.Int32
+	v := New(WithCollectAll(), WithConcurrentFields())
+	RegisterRuleCtx(v, "resolves", func(goctx context.Context, params []any) error {
+		if goctx.Value(lookupKey{}) == nil {
+			return errors.New("missing request context")
+		}
+		calls.Add(1)
+
+		host := params[0].(string)
+		delay, _ := time.ParseDuration(host[strings.IndexByte(host, '-')+1:])
+		select {
+		case <-time.After(delay):
+		case <-goctx.Done():
+			return goctx.Err()
+		}
+
+		if strings.HasPrefix(host, "bad") {
+			return errors.New("does not resolve")
+		}
+		return nil
+	})
+
+	valid := lookupHosts{Primary: "a-40ms", Secondary: "b-40ms", Backup: "c-40ms", Mirror: "d-40ms"}
+	valid.Owner.Host = "e-40ms"
+	// Later fields finish first, so merging in completion order would
+	// reverse the failures.
+	invalid := lookupHosts{Primary: "bad-50ms", Secondary: "b-1ms", Backup: "bad-30ms", Mirror: "bad-10ms"}
+	invalid.Owner.Host = "bad-1ms"
+
+	tests := []struct {
+		name       string
+		value      lookupHosts
+		wantFields []string
+	}{
+		{"all valid", valid, nil},
+		{"failures in field order", invalid, []string{"Primary", "Backup", "Mirror", "Owner.Host"}},
+	}
+
+	goctx := context.WithValue(context.Background(), lookupKey{}, "req-1")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 5 {
+				calls.Store(0)
+				start := time.Now()
+				err := v.ValidateCtx(goctx, tt.value)
+				if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+					t.Errorf("took %v, want the fields checked in parallel", elapsed)
+				}
+
+				if got := failedFields(err); !slices.Equal(got, tt.wantFields) {
+					t.Fatalf("failed fields = %v, want %v (err %v)", got, tt.wantFields, err)
+				}
+				if calls.Load() != 5 {
+					t.Errorf("resolves ran %d times, want 5", calls.Load())
+				}
+			}
+		})
+	}
+}
+
+func TestWordTagArgumentsStayStrings(t *testing.T) {
+	type reading struct {
+		Status string `validate:"oneOf=open:nan:inf"`
+	}
+
+	for _, status := range []string{"open", "nan", "inf"} {
+		if err := New().ValidateStruct(reading{Status: status}); err != nil {
+			t.Errorf("Status %q: %v", status, err)
+		}
+	}
+}
+
+type groupedContact struct {
+	_      struct{} `validate:"oneOfGroups=email:post"`
+	Name   string   `validate:"notEmpty"`
+	Email  string   `validate:"isEmail" group:"email"`
+	Street string   `validate:"notEmpty" group:"post"`
+	City   string   `validate:"notEmpty" group:"post"`
+}
+
+type groupsWithoutNames struct {
+	_     struct{} `validate:"oneOfGroups="`
+	Email string   `validate:"isEmail" group:"email"`
+}
+
+type groupsWithEmptyName struct {
+	_     struct{} `validate:"oneOfGroups=email:"`
+	Email string   `validate:"isEmail" group:"email"`
+}
+
+type groupsWithUnknownGroup struct {
+	_     struct{} `validate:"oneOfGroups=email:phone"`
+	Email string   `validate:"isEmail" group:"email"`
+}
+
+func TestOneOfGroups(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		value      any
+		wantFields []string
+		wantSystem bool
+	}{
+		{"no group set", []Option{WithCollectAll()}, groupedContact{Name: "Ada"}, []string{"Email", "Street", "City"}, false},
+		{"no group set stops at the first", nil, groupedContact{Name: "Ada"}, []string{"Email"}, false},
+		{"email group set", nil, groupedContact{Name: "Ada", Email: "ada@example.com"}, nil, false},
+		{"post group set", nil, groupedContact{Name: "Ada", Street: "1 Main St", City: "Springfield"}, nil, false},
+		{"both groups set", nil, groupedContact{Name: "Ada", Email: "ada@example.com", Street: "1 Main St", City: "Springfield"}, nil, false},
+		{"post group partially set", []Option{WithCollectAll()}, groupedContact{Name: "Ada", Street: "1 Main St"}, []string{"Email", "City"}, false},
+		{"ungrouped fields still checked", []Option{WithCollectAll()}, groupedContact{Email: "ada@example.com"}, []string{"Name"}, false},
+		{"directive without groups", nil, groupsWithoutNames{Email: "ada@example.com"}, nil, true},
+		{"directive with an empty group", nil, groupsWithEmptyName{Email: "ada@example.com"}, nil, true},
+		{"directive with a group no field joins", nil, groupsWithUnknownGroup{}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := New(tt.opts...).ValidateStruct(tt.value)
+			if tt.wantSystem {
+				if err == nil || Classify(err) != SystemError || !strings.Contains(err.Error(), "oneOfGroups") {
+					t.Fatalf("got %v, want a system error about the directive", err)
+				}
+				if errs := New().CheckStructTags(tt.value); len(errs) == 0 {
+					t.Error("CheckStructTags did not report the directive")
+				}
+				return
+			}
+
+			if got := failedFields(err); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("failed fields = %v, want %v (err %v)", got, tt.wantFields, err)
+			}
+		})
+	}
+}
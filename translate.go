@@ -0,0 +1,123 @@
+package validator
+
+// AddTranslation sets the message template for ruleName in locale. Templates
+// use the placeholders of Messagef, so "doit contenir au moins {1}
+// caractères" works for minLength.
+func (v *Validator) AddTranslation(locale string, ruleName string, template string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.translations[locale] == nil {
+		v.translations[locale] = make(map[string]string)
+	}
+	v.translations[locale][ruleName] = template
+}
+
+// WithTranslations seeds the validator with message templates by locale and
+// then rule name, as if added with AddTranslation.
+func WithTranslations(translations map[string]map[string]string) Option {
+	return func(cfg *Config) {
+		if cfg.Translations == nil {
+			cfg.Translations = make(map[string]map[string]string, len(translations))
+		}
+		for locale, templates := range translations {
+			if cfg.Translations[locale] == nil {
+				cfg.Translations[locale] = make(map[string]string, len(templates))
+			}
+			for ruleName, template := range templates {
+				cfg.Translations[locale][ruleName] = template
+			}
+		}
+	}
+}
+
+// WithDefaultLocale sets the locale whose translations are used when a
+// context has no locale, or its locale has no translation for a rule.
+func WithDefaultLocale(locale string) Option {
+	return func(cfg *Config) {
+		cfg.DefaultLocale = locale
+	}
+}
+
+// Locale selects the translations used for failures recorded after it, and
+// by nested contexts created from ctx.
+func (ctx *ValidationContext) Locale(locale string) *ValidationContext {
+	ctx.locale = locale
+
+	return ctx
+}
+
+// ValidateLocale validates value like ValidateStruct, rendering failure
+// messages in locale. It is short for ValidateStruct with WithLocale.
+func (v *Validator) ValidateLocale(value any, locale string) error {
+	return v.ValidateStruct(value, WithLocale(locale))
+}
+
+// MessageResolver returns the message for a failure of rule with params,
+// the value being validated included, in locale, or false to keep the
+// rule's own message. Rule names are stable, so they make good keys for
+// translation catalogs.
+type MessageResolver func(rule string, params []any, locale string) (string, bool)
+
+// SetMessageResolver sets the resolver consulted first for the message of
+// every failure. When it returns false or an empty message, the template from
+// AddTranslation or SetMessage is used, and then the rule's own message. The
+// locale passed to it is the context's, or the default locale.
+func (v *Validator) SetMessageResolver(resolver MessageResolver) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.resolver = resolver
+}
+
+// message renders the message for a failure of ruleName with the message
+// resolver, or else from its template.
+func (v *Validator) message(locale string, ruleName string, params []any) (string, bool) {
+	resolverLocale := locale
+	if resolverLocale == "" {
+		resolverLocale = v.defaultLocale
+	}
+
+	for w := v; w != nil; w = w.parent {
+		w.mu.RLock()
+		resolver := w.resolver
+		w.mu.RUnlock()
+
+		if resolver != nil {
+			if message, ok := resolver(ruleName, params, resolverLocale); ok && message != "" {
+				return message, true
+			}
+			break
+		}
+	}
+
+	if template, ok := v.template(locale, ruleName); ok {
+		return expandMessage(template, params), true
+	}
+
+	return "", false
+}
+
+// template returns the message template for ruleName: the translation for
+// locale, then for the default locale, then the message set with SetMessage.
+func (v *Validator) template(locale string, ruleName string) (string, bool) {
+	if template, ok := v.localTemplate(locale, ruleName); ok || v.parent == nil {
+		return template, ok
+	}
+
+	return v.parent.template(locale, ruleName)
+}
+
+func (v *Validator) localTemplate(locale string, ruleName string) (string, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	for _, l := range []string{locale, v.defaultLocale} {
+		if template, ok := v.translations[l][ruleName]; ok && l != "" {
+			return template, true
+		}
+	}
+
+	template, ok := v.messages[ruleName]
+	return template, ok
+}
@@ -0,0 +1,129 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+type translatedSignup struct {
+	Name     string `validate:"notEmpty"`
+	Password string `validate:"minLength=8"`
+}
+
+func TestTranslations(t *testing.T) {
+	newValidator := func(opts ...Option) *Validator {
+		v := New(opts...)
+		v.AddTranslation("en", "notEmpty", "is required")
+		v.AddTranslation("en", "minLength", "needs at least {1} characters")
+		v.AddTranslation("fr", "notEmpty", "est obligatoire")
+		v.AddTranslation("fr", "minLength", "doit contenir au moins {1} caractères")
+		v.AddTranslation("de", "notEmpty", "ist erforderlich")
+		return v
+	}
+	value := translatedSignup{Password: "short"}
+
+	tests := []struct {
+		name    string
+		options []Option
+		locale  string
+		want    []string
+	}{
+		{"en", nil, "en", []string{"is required", "needs at least 8 characters"}},
+		{"fr", nil, "fr", []string{"est obligatoire", "doit contenir au moins 8 caractères"}},
+		{"missing key keeps the rule's message", nil, "de", []string{"ist erforderlich", "must be at least 8 characters, got 5"}},
+		{"missing key falls back to the default locale", []Option{WithDefaultLocale("en")}, "de", []string{"ist erforderlich", "needs at least 8 characters"}},
+		{"no locale uses the default", []Option{WithDefaultLocale("fr")}, "", []string{"est obligatoire", "doit contenir au moins 8 caractères"}},
+		{"unknown locale", nil, "es", []string{"required rule failed", "must be at least 8 characters, got 5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := newValidator(append(tt.options, WithCollectAll())...)
+			var got []string
+			for _, ve := range AsValidationErrors(v.ValidateStruct(value, WithLocale(tt.locale))) {
+				got = append(got, ve.Message)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("messages = %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("message %d = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContextLocale(t *testing.T) {
+	v := New(WithCollectAll())
+	v.AddTranslation("fr", "notEmpty", "est obligatoire")
+
+	ctx := v.newContext()
+	ctx.Field("A").Check("notEmpty", "")
+	ctx.Locale("fr").Field("B").Check("notEmpty", "")
+
+	got := AsValidationErrors(ctx.Err())
+	if len(got) != 2 || got[0].Message != "required rule failed" || got[1].Message != "est obligatoire" {
+		t.Errorf("messages = %+v, want the English then the French message", got)
+	}
+}
+
+func TestMessageResolver(t *testing.T) {
+	resolver := func(rule string, params []any, locale string) (string, bool) {
+		switch {
+		case rule == "notEmpty" && locale == "fr":
+			return "résolu : obligatoire", true
+		case rule == "minLength" && locale == "fr":
+			return "", true
+		case rule == "minLength":
+			return fmt.Sprintf("%s: at least %v (%s)", rule, params[1], locale), true
+		}
+		return "", false
+	}
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		locale string
+		want   []string
+	}{
+		{"resolver takes precedence over translations", nil, "fr", []string{"résolu : obligatoire", "doit contenir au moins 8 caractères"}},
+		{"resolver gets params and locale", nil, "de", []string{"ist erforderlich", "minLength: at least 8 (de)"}},
+		{"false falls back to the rule's message", nil, "es", []string{"required rule failed", "minLength: at least 8 (es)"}},
+		{"default locale is passed to the resolver", []Option{WithDefaultLocale("fr")}, "", []string{"résolu : obligatoire", "doit contenir au moins 8 caractères"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(append(tt.opts, WithCollectAll())...)
+			v.AddTranslation("fr", "notEmpty", "est obligatoire")
+			v.AddTranslation("fr", "minLength", "doit contenir au moins {1} caractères")
+			v.AddTranslation("de", "notEmpty", "ist erforderlich")
+			v.SetMessageResolver(resolver)
+
+			var got []string
+			for _, ve := range AsValidationErrors(v.ValidateStruct(translatedSignup{Password: "short"}, WithLocale(tt.locale))) {
+				got = append(got, ve.Message)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMessageResolverInherited(t *testing.T) {
+	parent := New()
+	parent.SetMessageResolver(func(rule string, params []any, locale string) (string, bool) {
+		return "from the parent", true
+	})
+	child := parent.Child()
+
+	ctx := child.newContext()
+	if err := ctx.Check("notEmpty", "").Err(); err == nil || err.Error() != "from the parent" {
+		t.Errorf("got %v, want the parent's resolver", err)
+	}
+}
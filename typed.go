@@ -0,0 +1,92 @@
+package validator
+
+import "reflect"
+
+// RegisterRule1 registers a rule taking exactly one parameter of type T. The
+// parameter is type-checked before fn runs, so fn needs no assertions.
+func RegisterRule1[T any](v *Validator, ruleName string, fn func(T) error) {
+	RegisterRuleArity(v, ruleName, 1, func(params []any) error {
+		if err := checkParamCount(ruleName, params, 1); err != nil {
+			return err
+		}
+
+		a, err := typedParam[T](ruleName, params, 0)
+		if err != nil {
+			return err
+		}
+
+		return fn(a)
+	})
+}
+
+// RegisterRule2 registers a rule taking exactly two parameters of types A
+// and B.
+func RegisterRule2[A, B any](v *Validator, ruleName string, fn func(A, B) error) {
+	RegisterRuleArity(v, ruleName, 2, func(params []any) error {
+		if err := checkParamCount(ruleName, params, 2); err != nil {
+			return err
+		}
+
+		a, err := typedParam[A](ruleName, params, 0)
+		if err != nil {
+			return err
+		}
+
+		b, err := typedParam[B](ruleName, params, 1)
+		if err != nil {
+			return err
+		}
+
+		return fn(a, b)
+	})
+}
+
+func checkParamCount(ruleName string, params []any, n int) error {
+	if len(params) != n {
+		return SystemErrorf("rule %s expects %d parameters, got %d", ruleName, n, len(params))
+	}
+
+	return nil
+}
+
+func typedParam[T any](ruleName string, params []any, i int) (T, error) {
+	var zero T
+
+	if params[i] == nil {
+		// nil is a valid value for interface, pointer, slice, map, func and chan types
+		switch reflect.TypeFor[T]().Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return zero, nil
+		}
+	}
+
+	value, ok := params[i].(T)
+	if !ok {
+		return zero, SystemErrorf("rule %s expects %v at position %d, got %T", ruleName, reflect.TypeFor[T](), i+1, params[i])
+	}
+
+	return value, nil
+}
+
+// RegisterSliceRule registers a rule taking a single slice of T, so rules over
+// collections need no element-wise reflection. Named slice types such as
+// type Tags []string are accepted too.
+func RegisterSliceRule[T any](v *Validator, ruleName string, fn func([]T) error) {
+	RegisterRuleArity(v, ruleName, 1, func(params []any) error {
+		if err := checkParamCount(ruleName, params, 1); err != nil {
+			return err
+		}
+
+		if s, ok := params[0].([]T); ok || params[0] == nil {
+			return fn(s)
+		}
+
+		sliceType := reflect.TypeFor[[]T]()
+		rv := reflect.ValueOf(params[0])
+		if !rv.IsValid() || rv.Kind() != reflect.Slice || !rv.Type().ConvertibleTo(sliceType) {
+			return SystemErrorf("rule %s expects %v, got %T", ruleName, sliceType, params[0])
+		}
+
+		return fn(rv.Convert(sliceType).Interface().([]T))
+	})
+}
@@ -0,0 +1,103 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRegisterRule1And2(t *testing.T) {
+	v := New()
+	RegisterRule1(v, "even", func(n int) error {
+		if n%2 != 0 {
+			return fmt.Errorf("%d is odd", n)
+		}
+		return nil
+	})
+	RegisterRule2(v, "prefixed", func(prefix string, s string) error {
+		if !strings.HasPrefix(s, prefix) {
+			return fmt.Errorf("must start with %q", prefix)
+		}
+		return nil
+	})
+	RegisterRule1(v, "nilable", func(p *int) error { return nil })
+
+	tests := []struct {
+		name       string
+		rule       string
+		params     []any
+		wantErr    bool
+		wantSystem bool
+	}{
+		{"one param passes", "even", []any{4}, false, false},
+		{"one param fails", "even", []any{3}, true, false},
+		{"one param wrong type", "even", []any{"4"}, true, true},
+		{"one param too many", "even", []any{4, 6}, true, true},
+		{"one param missing", "even", nil, true, true},
+		{"nil for a pointer", "nilable", []any{nil}, false, false},
+		{"nil for an int", "even", []any{nil}, true, true},
+		{"two params pass", "prefixed", []any{"ord-", "ord-42"}, false, false},
+		{"two params fail", "prefixed", []any{"ord-", "inv-42"}, true, false},
+		{"first param wrong type", "prefixed", []any{1, "ord-42"}, true, true},
+		{"second param wrong type", "prefixed", []any{"ord-", 42}, true, true},
+		{"two params too few", "prefixed", []any{"ord-"}, true, true},
+		{"two params too many", "prefixed", []any{"ord-", "ord-1", "ord-2"}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check(tt.rule, tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && (Classify(err) == SystemError) != tt.wantSystem {
+				t.Errorf("%v: system error = %v, want %v", err, Classify(err) == SystemError, tt.wantSystem)
+			}
+		})
+	}
+}
+
+type sliceTags []string
+
+func TestRegisterSliceRule(t *testing.T) {
+	v := New()
+	RegisterSliceRule(v, "noBlankTags", func(tags []string) error {
+		for i, tag := range tags {
+			if strings.TrimSpace(tag) == "" {
+				return fmt.Errorf("tag %d is blank", i)
+			}
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		params     []any
+		wantErr    bool
+		wantSystem bool
+	}{
+		{"valid slice", []any{[]string{"go", "json"}}, false, false},
+		{"empty slice", []any{[]string{}}, false, false},
+		{"nil", []any{nil}, false, false},
+		{"named slice type", []any{sliceTags{"go"}}, false, false},
+		{"failing element", []any{[]string{"go", " "}}, true, false},
+		{"wrong element type", []any{[]int{1, 2}}, true, true},
+		{"not a slice", []any{"go"}, true, true},
+		{"too many params", []any{[]string{"go"}, []string{"json"}}, true, true},
+		{"too few params", []any{}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check("noBlankTags", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && (Classify(err) == SystemError) != tt.wantSystem {
+				t.Errorf("%v: system error = %v, want %v", err, Classify(err) == SystemError, tt.wantSystem)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Validatable is implemented by types that validate themselves, so domain
+// packages can own their rules without a RegisterType call at startup. It is
+// used by every entry point, and for nested fields and collection elements,
+// when no handler is registered for the type; registered handlers, including
+// interface handlers, take precedence.
+type Validatable interface {
+	ValidateWith(ctx *ValidationContext)
+}
+
+var validatableType = reflect.TypeFor[Validatable]()
+
+// validatableHandler returns a handler calling ValidateWith for types that
+// implement Validatable themselves or through a pointer receiver.
+func validatableHandler(typ reflect.Type) (HandlerFunc, bool) {
+	if typ.Implements(validatableType) {
+		return func(s any, ctx *ValidationContext) {
+			if rv := reflect.ValueOf(s); rv.Kind() == reflect.Pointer && rv.IsNil() {
+				ctx.fail(fmt.Errorf("cannot validate nil %v", typ))
+				return
+			}
+
+			s.(Validatable).ValidateWith(ctx)
+		}, true
+	}
+
+	if typ.Kind() != reflect.Pointer && reflect.PointerTo(typ).Implements(validatableType) {
+		return func(s any, ctx *ValidationContext) {
+			// copy the value so the pointer receiver has an address
+			ptr := reflect.New(typ)
+			ptr.Elem().Set(reflect.ValueOf(s))
+			ptr.Interface().(Validatable).ValidateWith(ctx)
+		}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,122 @@
+package validator
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+type valueAccount struct {
+	Name string
+}
+
+func (a valueAccount) ValidateWith(ctx *ValidationContext) {
+	ctx.Field("Name").Check("notEmpty", a.Name)
+}
+
+type pointerAccount struct {
+	Name string
+}
+
+func (a *pointerAccount) ValidateWith(ctx *ValidationContext) {
+	ctx.Field("Name").Check("notEmpty", a.Name)
+}
+
+type taggedAccount struct {
+	Name  string
+	Email string `validate:"isEmail"`
+}
+
+func (a taggedAccount) ValidateWith(ctx *ValidationContext) {
+	ctx.Field("Name").Check("notEmpty", a.Name)
+}
+
+type accountList struct {
+	Owner    pointerAccount
+	Accounts []valueAccount
+}
+
+func (l accountList) ValidateWith(ctx *ValidationContext) {
+	ctx.Field("Owner").Validate(l.Owner)
+	ctx.Field("Accounts").ValidateEach(l.Accounts)
+}
+
+func TestValidatable(t *testing.T) {
+	v := New(WithCollectAll())
+
+	tests := []struct {
+		name       string
+		value      any
+		wantFields []string
+	}{
+		{"value receiver", valueAccount{}, []string{"Name"}},
+		{"value receiver through pointer", &valueAccount{}, []string{"Name"}},
+		{"value receiver passes", valueAccount{Name: "Ada"}, nil},
+		{"pointer receiver on value", pointerAccount{}, []string{"Name"}},
+		{"pointer receiver on pointer", &pointerAccount{}, []string{"Name"}},
+		{"pointer receiver passes", pointerAccount{Name: "Ada"}, nil},
+		{"tags run after ValidateWith", taggedAccount{Email: "x"}, []string{"Name", "Email"}},
+		{"tags pass", taggedAccount{Name: "Ada", Email: "ada@example.com"}, nil},
+		{"nested fields and elements", accountList{Accounts: []valueAccount{{Name: "Ada"}, {}}}, []string{"Owner.Name", "Accounts[1].Name"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.ValidateStruct(tt.value)
+			if got := failedFields(err); !slices.Equal(got, tt.wantFields) {
+				t.Fatalf("got failed fields %v (%v), want %v", got, err, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestValidatableErrors(t *testing.T) {
+	errClosed := errors.New("account closed")
+
+	t.Run("messages reach the caller", func(t *testing.T) {
+		v := New()
+		RegisterType(v, func(a valueAccount, ctx *ValidationContext) {
+			ctx.Field("Name").MustMessage("name is reserved", func() bool { return a.Name != "root" })
+		})
+
+		err := v.ValidateStruct(valueAccount{Name: "root"})
+		if err == nil || !strings.Contains(err.Error(), "name is reserved") {
+			t.Fatalf("got %v, want the registered handler's message", err)
+		}
+	})
+
+	t.Run("fatal errors are returned", func(t *testing.T) {
+		v := New()
+		ctx := v.newContext()
+		ctx.Validate(fatalAccount{err: errClosed})
+
+		if err := ctx.Err(); !errors.Is(err, errClosed) {
+			t.Fatalf("got %v, want %v", err, errClosed)
+		}
+	})
+
+	t.Run("nil pointer fails", func(t *testing.T) {
+		var a *pointerAccount
+		if err := New().ValidateStruct(a); err == nil {
+			t.Fatal("validating a nil pointer did not fail")
+		}
+	})
+}
+
+type fatalAccount struct {
+	err error
+}
+
+func (a fatalAccount) ValidateWith(ctx *ValidationContext) {
+	ctx.Fatal(a.err)
+}
+
+func TestRegisteredHandlerTakesPrecedence(t *testing.T) {
+	v := New()
+	RegisterType(v, func(a valueAccount, ctx *ValidationContext) {})
+
+	if err := v.ValidateStruct(valueAccount{}); err != nil {
+		t.Fatalf("got %v, want the registered handler to replace ValidateWith", err)
+	}
+}
@@ -0,0 +1,179 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidationError is a single failure in a form that maps onto a field of a
+// request, for building error responses.
+type ValidationError struct {
+	Field   string
+	Rule    string
+	Code    string
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+
+	return e.Field + ": " + e.Message
+}
+
+type ValidationErrors []ValidationError
+
+func (errs ValidationErrors) Error() string {
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// Map returns the first message for each field, e.g. {"Email": "must be
+// valid"}. Failures not tied to a field are keyed by the empty string.
+func (errs ValidationErrors) Map() map[string]string {
+	m := make(map[string]string, len(errs))
+	for _, e := range errs {
+		if _, ok := m[e.Field]; !ok {
+			m[e.Field] = e.Message
+		}
+	}
+
+	return m
+}
+
+// AsValidationErrors converts the failures in err, as returned by the
+// validation entry points, into ValidationErrors. As in MarshalErrors, the
+// messages of system errors are replaced with a generic one.
+func AsValidationErrors(err error) ValidationErrors {
+	var errs ValidationErrors
+	for _, e := range flattenErrors(err) {
+		ve := ValidationError{Message: e.Error()}
+
+		var fe *FieldError
+		if errors.As(e, &fe) {
+			ve.Field = fe.Field
+			ve.Message = fe.Err.Error()
+		}
+
+		var re *RuleError
+		if errors.As(e, &re) {
+			ve.Rule = re.Rule
+			ve.Code = re.Code
+		}
+
+		if Classify(e) == SystemError {
+			ve.Message = internalErrorMessage
+		}
+
+		errs = append(errs, ve)
+	}
+
+	return errs
+}
+
+// RuleError is recorded for every failed Check and Must, so callers can use
+// errors.As and switch on Code. Rules may return a *RuleError to choose their
+// own Code; otherwise Code is derived from the rule name, e.g. "greater_than"
+// for greaterThan. Params holds every parameter the rule was called with.
+type RuleError struct {
+	Rule   string
+	Field  string
+	Params []any
+	Code   string
+	Err    error
+
+	// message overrides the display message, as set by Message.
+	message string
+	// excerpt is filled in for captures only.
+	excerpt string
+	// sensitive is set when the field's value must not be echoed back.
+	sensitive bool
+}
+
+func (e *RuleError) Error() string {
+	if e.message != "" {
+		return e.message
+	}
+
+	if e.Err == nil {
+		return e.Code
+	}
+
+	return e.Err.Error()
+}
+
+func (e *RuleError) Unwrap() error {
+	return e.Err
+}
+
+// ruleError records the rule, field and parameters of a failed check on err.
+func (ctx *ValidationContext) ruleError(ruleName string, params []any, err error) *RuleError {
+	re := &RuleError{Err: err}
+	var returned *RuleError
+	if errors.As(err, &returned) {
+		copied := *returned
+		re = &copied
+	}
+
+	if re.Rule == "" {
+		re.Rule = ruleName
+	}
+	if re.Field == "" {
+		re.Field = ctx.field
+	}
+	if re.Params == nil {
+		re.Params = append([]any(nil), params...)
+	}
+	if re.Code == "" {
+		re.Code = ruleCode(ruleName)
+	}
+	re.sensitive = ctx.sensitive
+
+	var ve *valueError
+	if re.sensitive && errors.As(err, &ve) {
+		re.message = ve.redacted
+	}
+
+	return re
+}
+
+// ruleCode converts a camelCase rule name to snake_case: isURL becomes
+// is_url and greaterThan becomes greater_than.
+func ruleCode(ruleName string) string {
+	runes := []rune(ruleName)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
+
+// valueError is a failure whose message echoes the values involved. For
+// sensitive fields Check uses the redacted message instead.
+type valueError struct {
+	message  string
+	redacted string
+}
+
+func valueErrorf(redacted string, format string, args ...any) error {
+	return &valueError{message: fmt.Sprintf(format, args...), redacted: redacted}
+}
+
+func (e *valueError) Error() string {
+	return e.message
+}
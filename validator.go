@@ -1,274 +1,2351 @@
 package validator
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"net/mail"
+	"net/url"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
 ) //
 
 type ValidationContext struct {
-	validator *Validator
-	err       error
+	validator  *Validator
+	err        error
+	collectAll bool
+	errs       []error
+	lastFailed bool
+	prefix     string
+	field      string
+	plan       *Plan
+	fatal      error
+	warnings   []Warning
+	visited    map[visit]bool
+	depth      int
+	params     map[string]any
+	memo       map[any]memoEntry
+	sensitive  bool
+	locale     string
+	conditions []bool
+	absent     bool
+	key        any
+	goctx      context.Context
+	groups     []*pendingGroup
+	halt       *groupHalt
+	scenario   string
+}
+
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
 }
 type HandlerFunc func(a any, ctx *ValidationContext)
 type RuleFunc func(param []any) error
 type Validator struct {
-	rules        map[string]RuleFunc
-	typeHandlers map[reflect.Type]HandlerFunc
+	mu              sync.RWMutex
+	rules           map[string]RuleFunc
+	arities         map[string]int
+	typeHandlers    map[reflect.Type]HandlerFunc
+	interfaces      []reflect.Type
+	collectAll      bool
+	strict          bool
+	withoutBuiltins bool
+	logger          Logger
+	propagatePanics bool
+	now             func() time.Time
+	grandfather     grandfathering
+	patterns        sync.Map
+	formats         map[string]string
+	units           map[string]unit
+	capture         func(Capture)
+	excerptRunes    int
+	redactValues    bool
+	enums           map[string][]any
+	messages        map[string]string
+	translations    map[string]map[string]string
+	defaultLocale   string
+	ctxRules        map[string]RuleFuncCtx
+	jsonTagNames    bool
+	middleware      []Middleware
+	hashVerifiers   map[string]HashVerifier
+	cfg             Config
+	custom          map[string]bool
+	composites      map[string][]RuleStep
+	scenarios       map[scenarioKey]HandlerFunc
+	parent          *Validator
+	resolver        MessageResolver
+	concurrent      bool
+	failFastGroups  bool
+}
+
+// Option configures a validator created with New by filling in its Config.
+type Option func(cfg *Config)
+
+// WithStrict makes configuration mistakes such as unknown rule names panic
+// instead of failing validation, for fast failure during development.
+func WithStrict() Option {
+	return func(cfg *Config) {
+		cfg.Strict = true
+	}
+}
+
+// WithoutBuiltins creates a validator with none of the built-in rules.
+func WithoutBuiltins() Option {
+	return func(cfg *Config) {
+		cfg.WithoutBuiltins = true
+	}
+}
+
+// WithRules seeds the validator with rules. Seeded rules replace built-ins
+// of the same name.
+func WithRules(rules map[string]RuleFunc) Option {
+	return func(cfg *Config) {
+		if cfg.Rules == nil {
+			cfg.Rules = make(map[string]RuleFunc, len(rules))
+		}
+		for name, fnc := range rules {
+			cfg.Rules[name] = fnc
+		}
+	}
+}
+
+// UnknownRuleError reports a check against a rule that has not been
+// registered.
+type UnknownRuleError struct {
+	Name string
+}
+
+func (e *UnknownRuleError) Error() string {
+	return "rule " + e.Name + " has not been registered to specified validator"
+}
+
+// WithCollectAll makes every ValidationContext created by the validator
+// accumulate failures instead of stopping at the first one.
+func WithCollectAll() Option {
+	return func(cfg *Config) {
+		cfg.CollectAll = true
+	}
+}
+
+// RegisterRule registers fnc as ruleName, returning the rule it replaces, if
+// any, so callers can restore it.
+func RegisterRule(v *Validator, ruleName string, fnc RuleFunc) (previous RuleFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	previous = v.rules[ruleName]
+	v.rules[ruleName] = fnc
+	delete(v.arities, ruleName)
+	delete(v.ctxRules, ruleName)
+	v.markCustom(ruleName)
+
+	return previous
+}
+
+// UnregisterRule removes ruleName, reporting whether it was registered.
+func UnregisterRule(v *Validator, ruleName string) bool {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	_, ok := v.rules[ruleName]
+	delete(v.rules, ruleName)
+	delete(v.arities, ruleName)
+	delete(v.ctxRules, ruleName)
+	v.markCustom(ruleName)
+
+	return ok
+}
+
+// HasRule reports whether ruleName is registered, so callers can check before
+// a Check that would otherwise fail, or panic in strict mode.
+func (v *Validator) HasRule(ruleName string) bool {
+	_, ok := v.rule(ruleName)
+	return ok
+}
+
+// RegisterRuleArity registers a rule that needs at least minArity parameters,
+// so call sites can be verified up front with CheckArity.
+func RegisterRuleArity(v *Validator, ruleName string, minArity int, fnc RuleFunc) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.rules[ruleName] = fnc
+	v.arities[ruleName] = minArity
+	delete(v.ctxRules, ruleName)
+	v.markCustom(ruleName)
+}
+
+// WithRule replaces ruleName with fnc while body runs and restores the
+// previous rule afterwards, even if body panics. The replacement is visible
+// to every goroutine using v, so it is meant for tests.
+func (v *Validator) WithRule(ruleName string, fnc RuleFunc, body func()) {
+	v.mu.Lock()
+	previous, hadRule := v.rules[ruleName]
+	arity, hadArity := v.arities[ruleName]
+	previousCtx, hadCtx := v.ctxRules[ruleName]
+	wasCustom := v.custom[ruleName]
+	v.rules[ruleName] = fnc
+	delete(v.ctxRules, ruleName)
+	// marked custom so that it also replaces a parent's rule of that name
+	v.custom[ruleName] = true
+	v.mu.Unlock()
+
+	defer func() {
+		v.mu.Lock()
+		defer v.mu.Unlock()
+
+		delete(v.rules, ruleName)
+		if hadRule {
+			v.rules[ruleName] = previous
+		}
+
+		delete(v.arities, ruleName)
+		if hadArity {
+			v.arities[ruleName] = arity
+		}
+
+		if hadCtx {
+			v.ctxRules[ruleName] = previousCtx
+		}
+
+		if !wasCustom {
+			delete(v.custom, ruleName)
+		}
+	}()
+
+	body()
+}
+
+// RegisterAllowlistRule registers a rule that passes when every parameter is
+// accepted by loader. The loader is only consulted when the rule runs, so
+// large allowlists can live in a cache or store instead of in memory.
+func RegisterAllowlistRule(v *Validator, ruleName string, loader func(value string) (bool, error)) {
+	RegisterRuleArity(v, ruleName, 1, func(params []any) error {
+		for _, p := range params {
+			value, ok := p.(string)
+			if !ok {
+				return SystemErrorf("%s: unsupported type %T", ruleName, p)
+			}
+
+			allowed, err := loader(value)
+			if err != nil {
+				return SystemErrorf("%s: %w", ruleName, err)
+			}
+
+			if !allowed {
+				return fmt.Errorf("%s: %q is not allowed", ruleName, value)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (v *Validator) CheckArity(ruleName string, n int) error {
+	_, minArity, ok := v.ruleArity(context.Background(), ruleName)
+	if !ok {
+		return &UnknownRuleError{Name: ruleName}
+	}
+
+	if n < minArity {
+		return fmt.Errorf("%s: expected at least %d parameters, got %d", ruleName, minArity, n)
+	}
+
+	return nil
+}
+
+// RegisterType registers the handler for values of type T. T may be an
+// interface type, in which case the handler also runs for every value whose
+// type implements T and has no handler of its own. A value is dispatched to,
+// in order of precedence, the handler for its exact type, then for a pointer
+// the handler for the exact type it points to, and otherwise the first
+// registered interface handler its type implements.
+func RegisterType[T any](v *Validator, handler func(s T, ctx *ValidationContext)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	typ := reflect.TypeFor[T]()
+	if _, ok := v.typeHandlers[typ]; !ok && typ.Kind() == reflect.Interface {
+		v.interfaces = append(v.interfaces, typ)
+	}
+
+	v.typeHandlers[typ] = func(a any, cc *ValidationContext) {
+		handler(a.(T), cc)
+	}
+}
+
+func (v *Validator) rule(ruleName string) (RuleFunc, bool) {
+	rule, _, ok := v.ruleArity(context.Background(), ruleName)
+	return rule, ok
+}
+
+// ruleArity looks up a rule and its minimum arity under a single read lock,
+// since it sits on the hot path of every Check.
+// Rules registered after the built-ins, on v or on one of its parents, take
+// precedence over v's built-ins; see Child.
+func (v *Validator) ruleArity(goctx context.Context, ruleName string) (RuleFunc, int, bool) {
+	owner := v
+	for w := v; w != nil; w = w.parent {
+		w.mu.RLock()
+		custom := w.custom[ruleName]
+		w.mu.RUnlock()
+
+		if custom {
+			owner = w
+			break
+		}
+	}
+
+	return owner.localRule(goctx, ruleName)
+}
+
+func (v *Validator) localRule(goctx context.Context, ruleName string) (RuleFunc, int, bool) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if fnc, ok := v.ctxRules[ruleName]; ok {
+		return func(params []any) error { return fnc(goctx, params) }, v.arities[ruleName], true
+	}
+
+	rule, ok := v.rules[ruleName]
+	return rule, v.arities[ruleName], ok
+}
+
+func (v *Validator) handler(typ reflect.Type) (HandlerFunc, bool) {
+	if typ == nil {
+		return nil, false
+	}
+
+	for w := v; w != nil; w = w.parent {
+		w.mu.RLock()
+		handler, ok := w.typeHandlers[typ]
+		w.mu.RUnlock()
+
+		if ok {
+			return handler, true
+		}
+	}
+
+	// leave pointers to types with their own handler to be dereferenced
+	for elem := typ; elem.Kind() == reflect.Pointer; {
+		elem = elem.Elem()
+		for w := v; w != nil; w = w.parent {
+			w.mu.RLock()
+			_, ok := w.typeHandlers[elem]
+			w.mu.RUnlock()
+
+			if ok {
+				return nil, false
+			}
+		}
+	}
+
+	for w := v; w != nil; w = w.parent {
+		w.mu.RLock()
+		for _, iface := range w.interfaces {
+			if typ.Implements(iface) {
+				handler := w.typeHandlers[iface]
+				w.mu.RUnlock()
+				return handler, true
+			}
+		}
+		w.mu.RUnlock()
+	}
+
+	return validatableHandler(typ)
+}
+
+// FieldError is produced for failures recorded while a field is active on the
+// ValidationContext.
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FatalError marks a failure that aborted validation. It is returned on its
+// own, without any failures collected before it.
+type FatalError struct {
+	Err error
+}
+
+func (e *FatalError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FatalError) Unwrap() error {
+	return e.Err
+}
+
+// Fatal stops validation immediately: every later check, including the rest
+// of a struct walk, is skipped and err becomes the result.
+func (ctx *ValidationContext) Fatal(err error) *ValidationContext {
+	if ctx.fatal != nil || err == nil {
+		return ctx
+	}
+
+	if ctx.field != "" {
+		err = &FieldError{Field: ctx.field, Err: err}
+	}
+	ctx.fatal = &FatalError{Err: err}
+
+	return ctx
+}
+
+// Field scopes the checks that follow it to the named field, until the next
+// call to Field.
+func (ctx *ValidationContext) Field(name string) *ValidationContext {
+	ctx.field = joinPath(ctx.prefix, name)
+	ctx.sensitive = false
+	ctx.absent = false
+
+	return ctx
+}
+
+func joinPath(prefix string, name string) string {
+	if prefix == "" || name == "" {
+		return prefix + name
+	}
+
+	return prefix + "." + name
+}
+
+// CollectAll switches the context from stopping at the first failure to
+// running every Check/Must and accumulating their errors.
+func (ctx *ValidationContext) CollectAll() *ValidationContext {
+	if !ctx.collectAll && ctx.err != nil {
+		ctx.errs = append(ctx.errs, ctx.err)
+	}
+	ctx.collectAll = true
+
+	return ctx
+}
+
+// Message replaces the message of the last failure. The original error
+// stays available through errors.Unwrap and errors.As, so the technical
+// cause can still be logged.
+func (ctx *ValidationContext) Message(message string) *ValidationContext {
+	if !ctx.lastFailed {
+		return ctx
+	}
+
+	if ctx.collectAll {
+		last := len(ctx.errs) - 1
+		ctx.errs[last] = replaceMessage(ctx.errs[last], message)
+	} else {
+		ctx.err = replaceMessage(ctx.err, message)
+	}
+
+	return ctx
+}
+
+func replaceMessage(err error, message string) error {
+	if fe, ok := err.(*FieldError); ok {
+		return &FieldError{Field: fe.Field, Err: replaceMessage(fe.Err, message)}
+	}
+
+	if re, ok := err.(*RuleError); ok {
+		replaced := *re
+		replaced.message = message
+		return &replaced
+	}
+
+	return &messageError{message: message, err: err}
+}
+
+// messageError is a failure other than a RuleError whose message was
+// replaced with Message.
+type messageError struct {
+	message string
+	err     error
+}
+
+func (e *messageError) Error() string {
+	return e.message
+}
+
+func (e *messageError) Unwrap() error {
+	return e.err
+}
+
+func (ctx *ValidationContext) Check(handlerName string, params ...any) *ValidationContext {
+	return ctx.check(ctx.context(), handlerName, params, false)
+}
+
+// Not is Check with the outcome inverted: it fails when the rule passes and
+// passes when the rule fails, e.g. Not("isEmail", username). The failure is
+// a RuleError whose Code is the rule's prefixed with not_, and its message
+// template is looked up under "not " followed by the rule name. Panics,
+// unknown rules and other system errors still fail.
+func (ctx *ValidationContext) Not(handlerName string, params ...any) *ValidationContext {
+	return ctx.check(ctx.context(), handlerName, params, true)
+}
+
+// MustCheck is Check for callers that treat an unregistered rule as a
+// programming error: it panics instead of recording an UnknownRuleError,
+// whether or not the validator is strict.
+func (ctx *ValidationContext) MustCheck(handlerName string, params ...any) *ValidationContext {
+	if _, _, ok := ctx.validator.ruleArity(context.Background(), handlerName); !ok {
+		panic("Rule " + handlerName + " has not been registered to specified validator")
+	}
+
+	return ctx.Check(handlerName, params...)
+}
+
+// check runs a rule, passing goctx to rules registered with RegisterRuleCtx.
+// With negate, the rule's outcome is inverted as described on Not.
+func (ctx *ValidationContext) check(goctx context.Context, handlerName string, params []any, negate bool) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	if err := goctx.Err(); err != nil && ctx.plan == nil {
+		ctx.Fatal(asSystemError(err))
+		return ctx
+	}
+
+	rule, minArity, ok := ctx.validator.ruleArity(goctx, handlerName)
+	if !ok {
+		if ctx.validator.strict {
+			panic("Rule " + handlerName + " has not been registered to specified validator")
+		}
+
+		ctx.usageError(&UnknownRuleError{Name: handlerName})
+		return ctx
+	}
+
+	if ctx.plan != nil {
+		if negate {
+			ctx.plan.add(ctx.field, negatedRule(handlerName), params)
+		} else {
+			ctx.plan.add(ctx.field, handlerName, params)
+		}
+		return ctx
+	}
+
+	if len(params) < minArity {
+		ctx.usageError(fmt.Errorf("%s: expected at least %d parameters, got %d", handlerName, minArity, len(params)))
+		return ctx
+	}
+
+	params, err := ctx.resolveParams(handlerName, params)
+	if err != nil {
+		ctx.usageError(err)
+		return ctx
+	}
+
+	err = ctx.callRule(handlerName, ctx.validator.wrap(rule), params)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		err = asSystemError(err)
+	}
+
+	messageName := handlerName
+	if negate {
+		err = negateResult(handlerName, err)
+		messageName = negatedRule(handlerName)
+	}
+
+	if err != nil && ctx.validator.isGrandfathered(handlerName) {
+		ctx.warnings = append(ctx.warnings, Warning{
+			Field:         ctx.field,
+			Rule:          handlerName,
+			Err:           err,
+			Grandfathered: true,
+		})
+		err = nil
+	}
+
+	if err != nil {
+		re := ctx.ruleError(handlerName, params, err)
+		if message, ok := ctx.validator.message(ctx.locale, messageName, re.Params); ok {
+			re.message = message
+		}
+		ctx.captureDetails(re)
+		err = re
+	}
+
+	ctx.fail(err)
+	return ctx
+}
+
+// negatedRule is the name a negated rule is planned and translated under.
+func negatedRule(ruleName string) string {
+	return "not " + ruleName
+}
+
+// negateResult inverts the result of a rule run by Not. System errors, such
+// as a panic in the rule, are failures either way.
+func negateResult(ruleName string, err error) error {
+	if err == nil {
+		return &RuleError{Code: "not_" + ruleCode(ruleName), Err: errors.New("must not pass " + ruleName)}
+	}
+
+	if Classify(err) == SystemError {
+		return err
+	}
+
+	return nil
+}
+
+func (ctx *ValidationContext) Must(fnc func() bool) *ValidationContext {
+	return ctx.MustMessage("rule failed", fnc)
+}
+
+// MustMessage is Must with the error message to use when fnc fails.
+func (ctx *ValidationContext) MustMessage(msg string, fnc func() bool) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	if ctx.plan != nil {
+		ctx.plan.add(ctx.field, "must", nil)
+		return ctx
+	}
+
+	err := ctx.callRule("must", func([]any) error {
+		if !fnc() {
+			return errors.New(msg)
+		}
+		return nil
+	}, nil)
+
+	if err != nil {
+		ctx.fail(&RuleError{Rule: "must", Field: ctx.field, Code: "must", Err: err})
+	} else {
+		ctx.fail(nil)
+	}
+
+	return ctx
+}
+
+// Err returns the first failure, or every failure joined when collecting.
+func (ctx *ValidationContext) Err() error {
+	ctx.Wait()
+	if ctx.fatal != nil {
+		return ctx.fatal
+	}
+
+	if ctx.collectAll {
+		return errors.Join(ctx.errs...)
+	}
+
+	return ctx.err
+}
+
+func (ctx *ValidationContext) Errors() []error {
+	ctx.Wait()
+	if ctx.fatal != nil {
+		return []error{ctx.fatal}
+	}
+
+	if ctx.collectAll {
+		return append([]error(nil), ctx.errs...)
+	}
+
+	if ctx.err != nil {
+		return []error{ctx.err}
+	}
+
+	return nil
+}
+
+// ByField groups the failures recorded so far by field path, in the order
+// they were recorded. Failures not tied to a field are keyed by the empty
+// string, and fields without failures are absent.
+func (ctx *ValidationContext) ByField() map[string][]error {
+	grouped := make(map[string][]error)
+	for _, err := range ctx.Errors() {
+		var fe *FieldError
+		if errors.As(err, &fe) {
+			grouped[fe.Field] = append(grouped[fe.Field], fe.Err)
+			continue
+		}
+
+		grouped[""] = append(grouped[""], err)
+	}
+
+	return grouped
+}
+
+func (ctx *ValidationContext) skip() bool {
+	return ctx.fatal != nil || (!ctx.collectAll && ctx.err != nil) || ctx.inactive()
+}
+
+func (ctx *ValidationContext) fail(err error) {
+	ctx.lastFailed = err != nil
+	if err == nil {
+		return
+	}
+
+	if ctx.field != "" {
+		err = &FieldError{Field: ctx.field, Err: err}
+	}
+
+	ctx.record(err)
+}
+
+// record adds an error that already carries its field path.
+func (ctx *ValidationContext) record(err error) {
+	ctx.lastFailed = true
+	if ctx.collectAll {
+		ctx.errs = append(ctx.errs, err)
+		return
+	}
+
+	ctx.err = err
+}
+
+// ValidateStruct runs the handler registered for s, then any struct-level
+// directives declared on a blank field, e.g.
+//
+//	_ struct{} `validate:"allFields=notEmpty"`
+//
+// and finally the validate tags on its fields. With a oneOfGroups directive,
+// fields tagged with one of the named groups only need to be valid for one
+// group. Pointers are dereferenced when no handler is registered for the
+// pointer type itself. It is the same as v.ValidateStruct(s).
+func ValidateStruct[T any](v *Validator, s T) error {
+	return v.ValidateStruct(s)
+}
+
+func (v *Validator) validateStruct(ctx *ValidationContext, s any) bool {
+	typ := reflect.TypeOf(s)
+	if typ == nil {
+		return false
+	}
+
+	handler, ok := v.scenarioHandler(typ, ctx.scenario)
+	if !ok && typ != nil && typ.Kind() == reflect.Pointer {
+		rv := reflect.ValueOf(s)
+		if rv.IsNil() {
+			if !v.canValidate(typ.Elem()) {
+				return false
+			}
+
+			ctx.fail(fmt.Errorf("cannot validate nil %v", typ))
+			return true
+		}
+
+		if !ctx.visit(rv) {
+			return true
+		}
+		defer ctx.leave(rv)
+
+		return v.validateStruct(ctx, rv.Elem().Interface())
+	}
+
+	schema := schemaFor(typ)
+	if !ok && !schema.hasAllFields && !schema.tagged {
+		return false
+	}
+
+	if ok {
+		handler(s, ctx)
+	}
+
+	// allFields reports every failing field of this struct without making
+	// the rest of the run collect all failures
+	if schema.hasAllFields && !ctx.skip() {
+		child := ctx.child()
+		child.prefix, child.field = ctx.prefix, ctx.prefix
+		child.CollectAll()
+		checkAllFields(&child, reflect.ValueOf(s), schema.allFields)
+		ctx.merge(&child)
+	}
+
+	if schema.tagged {
+		v.checkTags(ctx, reflect.ValueOf(s))
+	}
+
+	return true
+}
+
+// visit marks a pointer as being validated, reporting false if it already is
+// further up the current path, which means the data has a cycle.
+func (ctx *ValidationContext) visit(ptr reflect.Value) bool {
+	key := visit{ptr: ptr.Pointer(), typ: ptr.Type()}
+	if ctx.visited[key] {
+		return false
+	}
+
+	if ctx.visited == nil {
+		ctx.visited = make(map[visit]bool)
+	}
+	ctx.visited[key] = true
+
+	return true
+}
+
+// leave unmarks a pointer once its validation has finished.
+func (ctx *ValidationContext) leave(ptr reflect.Value) {
+	delete(ctx.visited, visit{ptr: ptr.Pointer(), typ: ptr.Type()})
+}
+
+func (v *Validator) canValidate(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		if _, ok := v.handler(typ); ok {
+			return true
+		}
+		typ = typ.Elem()
+	}
+
+	_, ok := v.handler(typ)
+	schema := schemaFor(typ)
+	return ok || schema.hasAllFields || schema.tagged
+}
+
+// ruleKinds lists the field kinds a built-in rule can meaningfully be applied
+// to by a struct-level directive. Rules not listed apply to every field.
+var ruleKinds = map[string][]reflect.Kind{
+	"notEmpty":       {reflect.String, reflect.Array, reflect.Slice, reflect.Map},
+	"greaterThan":    numericAndLengthKinds,
+	"lessThan":       numericAndLengthKinds,
+	"isEmail":        {reflect.String},
+	"isNumeric":      {reflect.String},
+	"isAlpha":        {reflect.String},
+	"isAlphanumeric": {reflect.String},
+	"minLength":      lengthKinds,
+	"maxLength":      lengthKinds,
+	"lengthBetween":  lengthKinds,
+	"exactLength":    lengthKinds,
+}
+
+var lengthKinds = []reflect.Kind{reflect.String, reflect.Array, reflect.Slice, reflect.Map}
+
+var numericAndLengthKinds = []reflect.Kind{
+	reflect.String, reflect.Array, reflect.Slice, reflect.Map,
+	reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+	reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+	reflect.Float32, reflect.Float64,
+}
+
+func structDirective(typ reflect.Type, name string) (string, bool) {
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Name != "_" {
+			continue
+		}
+
+		for _, directive := range strings.Split(field.Tag.Get("validate"), ",") {
+			key, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if key == name {
+				return value, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func checkAllFields(ctx *ValidationContext, rv reflect.Value, ruleName string) {
+	kinds, restricted := ruleKinds[ruleName]
+	typ := rv.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if restricted && !containsKind(kinds, field.Type.Kind()) {
+			continue
+		}
+
+		ctx.Field(ctx.validator.fieldName(field)).Check(ruleName, rv.Field(i).Interface())
+	}
+	ctx.Field("")
+}
+
+func containsKind(kinds []reflect.Kind, kind reflect.Kind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *Validator) newContext() ValidationContext {
+	return ValidationContext{
+		validator:  v,
+		collectAll: v.collectAll,
+		memo:       make(map[any]memoEntry),
+	}
+}
+
+// maxValidationDepth caps how deeply Validate and ValidateEach may nest.
+const maxValidationDepth = 64
+
+// Validate runs the handler, directives and tags for value from inside
+// another handler. Failures are recorded on ctx under the current field.
+func (ctx *ValidationContext) Validate(value any) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	if ctx.depth >= maxValidationDepth {
+		ctx.usageError(fmt.Errorf("maximum validation depth of %d exceeded", maxValidationDepth))
+		return ctx
+	}
+
+	child := ctx.child()
+	if !ctx.validator.validateStruct(&child, value) {
+		ctx.usageError(fmt.Errorf("no type handler registered for %T", value))
+		return ctx
+	}
+
+	ctx.merge(&child)
+	return ctx
+}
+
+// ValidateEach calls Validate for every element of a slice, array or map,
+// qualifying the current field with the element's index or key.
+func (ctx *ValidationContext) ValidateEach(collection any) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	field := ctx.field
+	rv := reflect.ValueOf(collection)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len() && !ctx.skip(); i++ {
+			ctx.field = fmt.Sprintf("%s[%d]", field, i)
+			ctx.Validate(rv.Index(i).Interface())
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(rv) {
+			if ctx.skip() {
+				break
+			}
+
+			ctx.field = fmt.Sprintf("%s[%v]", field, key)
+			ctx.Validate(rv.MapIndex(key).Interface())
+		}
+	default:
+		ctx.usageError(fmt.Errorf("ValidateEach: expected a slice, array or map, got %T", collection))
+	}
+	ctx.field = field
+
+	return ctx
+}
+
+// Each calls fn for every element of a slice, array or map with a context
+// scoped to the element, so its failures are recorded under the current
+// field qualified with the index or key, e.g. Tags[2]. Iteration stops early
+// when ctx stops at the first failure. Key reports the element's index or
+// map key inside fn.
+func (ctx *ValidationContext) Each(collection any, fn func(elem any, ctx *ValidationContext)) *ValidationContext {
+	return ctx.each("Each", collection, fn)
+}
+
+// CheckEach checks every element of a slice, array or map against ruleName,
+// passing extraParams ahead of the element like the tag dialect does.
+// Failures are recorded under the element's index or key, e.g. Tags[3].
+func (ctx *ValidationContext) CheckEach(ruleName string, collection any, extraParams ...any) *ValidationContext {
+	return ctx.each("CheckEach", collection, func(elem any, ctx *ValidationContext) {
+		ctx.Check(ruleName, withSubject(ruleName, elem, extraParams)...)
+	})
+}
+
+func (ctx *ValidationContext) each(caller string, collection any, fn func(elem any, ctx *ValidationContext)) *ValidationContext {
+	if ctx.skipped() {
+		return ctx
+	}
+
+	field := ctx.field
+	each := func(key any, elem reflect.Value) {
+		ctx.field = fmt.Sprintf("%s[%v]", field, key)
+		child := ctx.child()
+		child.key = key
+		fn(elem.Interface(), &child)
+		ctx.merge(&child)
+	}
+
+	rv := reflect.ValueOf(collection)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len() && !ctx.skip(); i++ {
+			each(i, rv.Index(i))
+		}
+	case reflect.Map:
+		for _, key := range sortedKeys(rv) {
+			if ctx.skip() {
+				break
+			}
+
+			each(key.Interface(), rv.MapIndex(key))
+		}
+	default:
+		ctx.usageError(fmt.Errorf("%s: expected a slice, array or map, got %T", caller, collection))
+	}
+	ctx.field = field
+
+	return ctx
+}
+
+// Key returns the index or map key of the element being visited by Each, or
+// nil outside of it.
+func (ctx *ValidationContext) Key() any {
+	return ctx.key
+}
+
+// child creates a context for nested validation scoped under the current
+// field, sharing the run-wide state of ctx.
+func (ctx *ValidationContext) child() ValidationContext {
+	if ctx.visited == nil {
+		ctx.visited = make(map[visit]bool)
+	}
+
+	return ValidationContext{
+		validator:  ctx.validator,
+		collectAll: ctx.collectAll,
+		prefix:     ctx.field,
+		field:      ctx.field,
+		plan:       ctx.plan,
+		visited:    ctx.visited,
+		depth:      ctx.depth + 1,
+		params:     ctx.params,
+		memo:       ctx.memo,
+		locale:     ctx.locale,
+		goctx:      ctx.goctx,
+		scenario:   ctx.scenario,
+	}
+}
+
+// merge records the outcome of a child context on ctx.
+func (ctx *ValidationContext) merge(child *ValidationContext) {
+	ctx.warnings = append(ctx.warnings, child.warnings...)
+	if child.fatal != nil {
+		ctx.fatal = child.fatal
+		return
+	}
+
+	errs := child.Errors()
+	if !ctx.collectAll && len(errs) > 1 {
+		// ctx keeps a single failure, so a child that collected several
+		// reports them together
+		errs = []error{errors.Join(errs...)}
+	}
+	for _, err := range errs {
+		ctx.record(err)
+	}
+	ctx.lastFailed = len(errs) > 0
+}
+
+// Validate runs the handler registered for T. When T is an interface type,
+// such as any, the handler is looked up by the dynamic type of value. See
+// RegisterType for how handlers are matched; a nil pointer to a type with a
+// handler fails validation.
+func Validate[T any](v *Validator, value T) error {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Interface {
+		typ = reflect.TypeOf(value)
+	}
+
+	ctx := v.newContext()
+	var subject any = value
+	handler, ok := v.handler(typ)
+	for !ok && typ != nil && typ.Kind() == reflect.Pointer {
+		rv := reflect.ValueOf(subject)
+		if rv.IsNil() {
+			if _, ok := v.handler(typ.Elem()); !ok {
+				break
+			}
+			return fmt.Errorf("cannot validate nil %v", typ)
+		}
+
+		subject, typ = rv.Elem().Interface(), typ.Elem()
+		handler, ok = v.handler(typ)
+	}
+
+	if !ok {
+		return SystemErrorf("no type handler registered for %v", reflect.TypeOf(value))
+	}
+
+	handler(subject, &ctx)
+
+	return v.finish(&ctx, value)
+}
+
+func New(opts ...Option) *Validator { //
+	var cfg Config
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return newValidator(cfg)
+}
+
+func newValidator(cfg Config) *Validator {
+	validator := &Validator{
+		rules:           make(map[string]RuleFunc, 0),
+		arities:         make(map[string]int, 0),
+		ctxRules:        make(map[string]RuleFuncCtx, 0),
+		typeHandlers:    make(map[reflect.Type]HandlerFunc, 0),
+		formats:         make(map[string]string, 0),
+		units:           make(map[string]unit, 0),
+		enums:           make(map[string][]any, 0),
+		hashVerifiers:   make(map[string]HashVerifier, 0),
+		composites:      make(map[string][]RuleStep, 0),
+		scenarios:       make(map[scenarioKey]HandlerFunc, 0),
+		messages:        make(map[string]string, 0),
+		translations:    make(map[string]map[string]string, 0),
+		defaultLocale:   cfg.DefaultLocale,
+		collectAll:      cfg.CollectAll,
+		strict:          cfg.Strict,
+		withoutBuiltins: cfg.WithoutBuiltins,
+		logger:          cfg.Logger,
+		propagatePanics: cfg.PropagatePanics,
+		now:             cfg.Clock,
+		capture:         cfg.Capture,
+		excerptRunes:    cfg.CaptureExcerpts,
+		redactValues:    cfg.RedactValues,
+		jsonTagNames:    cfg.UseJSONTagNames,
+		concurrent:      cfg.ConcurrentFields,
+		failFastGroups:  cfg.FailFastGroups,
+		grandfather: grandfathering{
+			until:  make(map[string]time.Time, 0),
+			counts: make(map[string]int, 0),
+		},
+	}
+	if validator.logger == nil {
+		validator.logger = nopLogger{}
+	}
+	if validator.now == nil {
+		validator.now = time.Now
+	}
+
+	validator.cfg = cfg
+	validator.cfg.Rules = nil
+	validator.cfg.Translations = nil
+
+	if !validator.withoutBuiltins {
+		registerBuiltins(validator)
+		registerFormats(validator)
+		registerMeasurements(validator)
+		registerEnums(validator)
+		registerHashVerifiers(validator)
+
+		for name, fnc := range validator.rules {
+			if !rawParamRules[name] {
+				validator.rules[name] = derefParams(fnc)
+			}
+		}
+	}
+
+	// rules registered from here on are tracked so Clone can copy them
+	validator.custom = make(map[string]bool)
+
+	// rules seeded through the config take precedence over the built-ins
+	for name, fnc := range cfg.Rules {
+		RegisterRule(validator, name, fnc)
+	}
+
+	for locale, templates := range cfg.Translations {
+		for ruleName, template := range templates {
+			validator.AddTranslation(locale, ruleName, template)
+		}
+	}
+
+	return validator
+}
+
+func registerBuiltins(validator *Validator) {
+	RegisterRule(validator, "notEmpty", func(param []any) error {
+		for _, p := range param {
+
+			length := 0
+			rv := reflect.ValueOf(p)
+			kind := rv.Kind()
+
+			if kind == reflect.Array ||
+				kind == reflect.Slice ||
+				kind == reflect.Map {
+				length = rv.Len()
+			} else if kind == reflect.String {
+				length = len(strings.TrimSpace(p.(string)))
+			}
+
+			if length == 0 {
+				return errors.New("required rule failed")
+			}
+		}
+
+		return nil
+	})
+
+	// The comparison rules take the bound first, followed by the values to
+	// compare. Strings, slices, arrays and maps are compared by length;
+	// prefer minLength and maxLength, which say so in their messages.
+	RegisterRuleArity(validator, "greaterThan", 2, func(params []any) error {
+		return compareToBound("greaterThan", params, func(c int) bool { return c > 0 }, "greater than")
+	})
+
+	RegisterRuleArity(validator, "lessThan", 2, func(params []any) error {
+		return compareToBound("lessThan", params, func(c int) bool { return c < 0 }, "less than")
+	})
+
+	RegisterRuleArity(validator, "greaterOrEqual", 2, func(params []any) error {
+		return compareToBound("greaterOrEqual", params, func(c int) bool { return c >= 0 }, "greater than or equal to")
+	})
+
+	RegisterRuleArity(validator, "lessOrEqual", 2, func(params []any) error {
+		return compareToBound("lessOrEqual", params, func(c int) bool { return c <= 0 }, "less than or equal to")
+	})
+
+	// isEmail optionally takes a leading mode, "loose" (the default) or
+	// "strict", which also requires an alphabetic top-level domain.
+	RegisterRuleArity(validator, "isEmail", 1, func(param []any) error {
+		if len(param) == 0 {
+			return SystemErrorf("isEmail: expected 1 parameter, got 0")
+		}
+
+		strict := false
+		if len(param) > 1 {
+			switch param[0] {
+			case "strict":
+				strict = true
+			case "loose":
+			default:
+				return SystemErrorf("isEmail: unknown mode %v, expected strict or loose", param[0])
+			}
+		}
+
+		email, ok := param[len(param)-1].(string)
+		if !ok {
+			return SystemErrorf("isEmail: unsupported type %T", param[len(param)-1])
+		}
+
+		if !validEmail(email, strict) {
+			return errors.New("must be a valid email address")
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "jsonSafeNumber", 1, func(params []any) error {
+		for _, p := range params {
+			var raw string
+			switch n := p.(type) {
+			case json.Number:
+				raw = n.String()
+			case string:
+				raw = n
+			case []byte:
+				raw = string(n)
+			default:
+				return SystemErrorf("jsonSafeNumber: unsupported type %T", p)
+			}
+
+			var number json.Number
+			if err := json.Unmarshal([]byte(raw), &number); err != nil {
+				return fmt.Errorf("jsonSafeNumber: %q is not a valid JSON number", raw)
+			}
+
+			r, ok := new(big.Rat).SetString(number.String())
+			if !ok {
+				return fmt.Errorf("jsonSafeNumber: %q is not a valid JSON number", raw)
+			}
+
+			// a safe number survives decoding into float64, and integers must
+			// additionally fit into int64
+			if _, exact := r.Float64(); !exact {
+				return fmt.Errorf("jsonSafeNumber: %s cannot be represented exactly as a float64", raw)
+			}
+
+			if r.IsInt() && !r.Num().IsInt64() {
+				return fmt.Errorf("jsonSafeNumber: %s overflows int64", raw)
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "balancedBrackets", 1, func(params []any) error {
+		closers := map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+		for _, p := range params {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("balancedBrackets: unsupported type %T", p)
+			}
+
+			type opener struct {
+				r   rune
+				pos int
+			}
+			var stack []opener
+
+			pos := 0
+			for _, r := range str {
+				switch r {
+				case '(', '[', '{':
+					stack = append(stack, opener{r, pos})
+				case ')', ']', '}':
+					if len(stack) == 0 {
+						return fmt.Errorf("balancedBrackets: unexpected %q at index %d", r, pos)
+					}
+
+					top := stack[len(stack)-1]
+					if top.r != closers[r] {
+						return fmt.Errorf("balancedBrackets: %q at index %d does not close %q at index %d", r, pos, top.r, top.pos)
+					}
+					stack = stack[:len(stack)-1]
+				}
+				pos++
+			}
+
+			if len(stack) > 0 {
+				top := stack[len(stack)-1]
+				return fmt.Errorf("balancedBrackets: %q at index %d is never closed", top.r, top.pos)
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "regex", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("regex: expected 2 parameters, got %d", len(params))
+		}
+
+		subject, ok := params[0].(string)
+		if !ok {
+			return SystemErrorf("regex: unsupported type %T for subject", params[0])
+		}
+
+		pattern, ok := params[1].(string)
+		if !ok {
+			return SystemErrorf("regex: unsupported type %T for pattern", params[1])
+		}
+
+		re, err := validator.compilePattern(pattern)
+		if err != nil {
+			return SystemErrorf("regex: invalid pattern %q: %w", pattern, err)
+		}
+
+		if !re.MatchString(subject) {
+			return fmt.Errorf("regex: %q does not match %q", subject, pattern)
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "oneOf", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("oneOf: expected at least 2 parameters, got %d", len(params))
+		}
+
+		subject, allowed := params[0], params[1:]
+		for _, a := range allowed {
+			if reflect.DeepEqual(subject, a) {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("oneOf: %v is not one of %v", subject, allowed)
+	})
+
+	// minLength, maxLength and exactLength take the value first and the
+	// length second, e.g. Check("minLength", password, 8). Strings are
+	// measured in runes, so multibyte characters count once.
+	RegisterRuleArity(validator, "minLength", 2, func(params []any) error {
+		return checkLength("minLength", params, func(n, bound int) bool { return n >= bound }, "at least")
+	})
+
+	RegisterRuleArity(validator, "maxLength", 2, func(params []any) error {
+		return checkLength("maxLength", params, func(n, bound int) bool { return n <= bound }, "at most")
+	})
+
+	RegisterRuleArity(validator, "lengthBetween", 3, func(params []any) error {
+		if len(params) < 3 {
+			return SystemErrorf("lengthBetween: expected at least 3 parameters, got %d", len(params))
+		}
+
+		min, err := lengthBound("lengthBetween", params[0])
+		if err != nil {
+			return err
+		}
+
+		max, err := lengthBound("lengthBetween", params[1])
+		if err != nil {
+			return err
+		}
+
+		if min > max {
+			return SystemErrorf("lengthBetween: minimum %d is greater than maximum %d", min, max)
+		}
+
+		for _, p := range params[2:] {
+			n, unit, err := lengthOf("lengthBetween", p)
+			if err != nil {
+				return err
+			}
+
+			if n < min || n > max {
+				return fmt.Errorf("must be between %d and %d %s, got %d", min, max, unit, n)
+			}
+		}
+
+		return nil
+	})
+
+	// afterField and beforeField take the sibling field's name and value
+	// followed by the subject. Missing or zero times on either side pass, so
+	// presence is left to notEmpty.
+	RegisterRuleArity(validator, "afterField", 3, func(params []any) error {
+		return compareTimeField("afterField", params, func(subject, sibling time.Time) bool {
+			return subject.After(sibling)
+		}, "after")
+	})
+
+	RegisterRuleArity(validator, "beforeField", 3, func(params []any) error {
+		return compareTimeField("beforeField", params, func(subject, sibling time.Time) bool {
+			return subject.Before(sibling)
+		}, "before")
+	})
+
+	// between takes (min, max, values...) like the tag dialect, and both
+	// bounds are inclusive. Strings and collections are compared by length.
+	RegisterRuleArity(validator, "between", 3, func(params []any) error {
+		if len(params) < 3 {
+			return SystemErrorf("between: expected at least 3 parameters, got %d", len(params))
+		}
+
+		min, err := measure("between", params[0])
+		if err != nil {
+			return err
+		}
+
+		max, err := measure("between", params[1])
+		if err != nil {
+			return err
+		}
+
+		if min.cmp(max) > 0 {
+			return SystemErrorf("between: minimum %v is greater than maximum %v", min, max)
+		}
+
+		for _, p := range params[2:] {
+			val, err := measure("between", p)
+			if err != nil {
+				return err
+			}
+
+			if val.cmp(min) < 0 {
+				return fmt.Errorf("value %v not in range [%v, %v]: below minimum", val, min, max)
+			}
+
+			if val.cmp(max) > 0 {
+				return fmt.Errorf("value %v not in range [%v, %v]: above maximum", val, min, max)
+			}
+		}
+
+		return nil
+	})
+
+	// isbn accepts ISBN-10 and ISBN-13 values, ignoring hyphens. An optional
+	// leading parameter of 10 or 13 restricts it to one version.
+	RegisterRuleArity(validator, "isbn", 1, func(params []any) error {
+		if len(params) == 0 || len(params) > 2 {
+			return SystemErrorf("isbn: expected 1 or 2 parameters, got %d", len(params))
+		}
+
+		version := 0
+		if len(params) == 2 {
+			v, ok := params[0].(int)
+			if !ok || (v != 10 && v != 13) {
+				return SystemErrorf("isbn: version must be 10 or 13, got %v", params[0])
+			}
+			version = v
+		}
+
+		raw, ok := params[len(params)-1].(string)
+		if !ok {
+			return SystemErrorf("isbn: unsupported type %T", params[len(params)-1])
+		}
+
+		isbn := strings.ReplaceAll(raw, "-", "")
+		switch {
+		case len(isbn) == 10 && version != 13:
+			if !validISBN10(isbn) {
+				return fmt.Errorf("isbn: %q is not a valid ISBN-10", raw)
+			}
+		case len(isbn) == 13 && version != 10:
+			if !validISBN13(isbn) {
+				return fmt.Errorf("isbn: %q is not a valid ISBN-13", raw)
+			}
+		case version != 0:
+			return fmt.Errorf("isbn: %q is not an ISBN-%d", raw, version)
+		default:
+			return fmt.Errorf("isbn: %q is not an ISBN-10 or ISBN-13", raw)
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "exactLength", 2, func(params []any) error {
+		return checkLength("exactLength", params, func(n, bound int) bool { return n == bound }, "exactly")
+	})
+
+	// multipleOfField takes the sibling field's name and value followed by
+	// the subject. Only zero is a multiple of zero.
+	RegisterRuleArity(validator, "multipleOfField", 3, func(params []any) error {
+		if len(params) < 3 {
+			return SystemErrorf("multipleOfField: expected at least 3 parameters, got %d", len(params))
+		}
+
+		name, ok := params[0].(string)
+		if !ok {
+			return SystemErrorf("multipleOfField: unsupported type %T for field name", params[0])
+		}
+
+		increment, err := measureNumber("multipleOfField", params[1])
+		if err != nil {
+			return err
+		}
+
+		for _, p := range params[2:] {
+			val, err := measureNumber("multipleOfField", p)
+			if err != nil {
+				return err
+			}
+
+			if increment.rat.Sign() == 0 {
+				if val.rat.Sign() != 0 {
+					return fmt.Errorf("must be a multiple of %s (0), so only 0 is allowed", name)
+				}
+				continue
+			}
+
+			if !new(big.Rat).Quo(val.rat, increment.rat).IsInt() {
+				return fmt.Errorf("must be a multiple of %s (%v), got %v", name, increment, val)
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "isPercentEncoded", 1, func(params []any) error {
+		for _, p := range params {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("isPercentEncoded: unsupported type %T", p)
+			}
+
+			if _, err := url.PathUnescape(str); err != nil {
+				return fmt.Errorf("isPercentEncoded: %q has a malformed escape sequence", str)
+			}
+		}
+
+		return nil
+	})
+
+	// equals, notEquals, before and after take the reference value first,
+	// followed by the values compared with it. Numbers of different types
+	// compare by value; anything else is compared with reflect.DeepEqual.
+	RegisterRuleArity(validator, "equals", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("equals: expected at least 2 parameters, got %d", len(params))
+		}
+
+		for _, p := range params[1:] {
+			if !valuesEqual(params[0], p) {
+				return valueErrorf("must equal the expected value", "equals: %v does not equal %v", p, params[0])
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "notEquals", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("notEquals: expected at least 2 parameters, got %d", len(params))
+		}
+
+		for _, p := range params[1:] {
+			if valuesEqual(params[0], p) {
+				return valueErrorf("must not equal the given value", "notEquals: %v must differ from %v", p, params[0])
+			}
+		}
+
+		return nil
+	})
+
+	// before and after compare against the validator's clock when the
+	// reference is "now" or left out, e.g. Check("after", deadline).
+	RegisterRuleArity(validator, "before", 1, func(params []any) error {
+		return compareOrdered("before", validator.withNow(params), func(c int) bool { return c < 0 }, "before")
+	})
+
+	RegisterRuleArity(validator, "after", 1, func(params []any) error {
+		return compareOrdered("after", validator.withNow(params), func(c int) bool { return c > 0 }, "after")
+	})
+
+	RegisterRuleArity(validator, "increasing", 1, func(params []any) error {
+		return checkSequence("increasing", params, func(c int) bool { return c > 0 }, "greater than")
+	})
+
+	RegisterRuleArity(validator, "nonDecreasing", 1, func(params []any) error {
+		return checkSequence("nonDecreasing", params, func(c int) bool { return c >= 0 }, "at least")
+	})
+
+	// matches takes a pattern string or a compiled *regexp.Regexp first and
+	// requires every following param to match it.
+	RegisterRuleArity(validator, "matches", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("matches: expected at least 2 parameters, got %d", len(params))
+		}
+
+		var re *regexp.Regexp
+		switch p := params[0].(type) {
+		case *regexp.Regexp:
+			re = p
+		case string:
+			var err error
+			if re, err = validator.compilePattern(p); err != nil {
+				return SystemErrorf("matches: invalid pattern %q: %w", p, err)
+			}
+		default:
+			return SystemErrorf("matches: unsupported type %T for pattern", params[0])
+		}
+
+		for _, p := range params[1:] {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("matches: unsupported type %T", p)
+			}
+
+			if !re.MatchString(str) {
+				return fmt.Errorf("matches: %q does not match %q", str, re)
+			}
+		}
+
+		return nil
+	})
+
+	// eqField and neField compare the value with a sibling field, named
+	// first, without putting either value in the message.
+	RegisterRuleArity(validator, "eqField", 3, func(params []any) error {
+		return compareField("eqField", params, true)
+	})
+
+	RegisterRuleArity(validator, "neField", 3, func(params []any) error {
+		return compareField("neField", params, false)
+	})
+
+	// mapValues takes the map first, then the name of a rule and its extra
+	// params, and applies the rule to every value in key order.
+	RegisterRuleArity(validator, "mapValues", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("mapValues: expected at least 2 parameters, got %d", len(params))
+		}
+
+		rv := reflect.ValueOf(params[0])
+		if rv.Kind() != reflect.Map {
+			return SystemErrorf("mapValues: unsupported type %T, expected a map", params[0])
+		}
+
+		ruleName, ok := params[1].(string)
+		if !ok {
+			return SystemErrorf("mapValues: unsupported type %T for rule name", params[1])
+		}
+
+		rule, ok := validator.rule(ruleName)
+		if !ok {
+			return &UnknownRuleError{Name: ruleName}
+		}
+
+		extra := params[2:]
+		for _, key := range sortedKeys(rv) {
+			value := rv.MapIndex(key).Interface()
+			var err error
+			if subjectFirstRules[ruleName] {
+				err = rule(append([]any{value}, extra...))
+			} else {
+				err = rule(append(extra[:len(extra):len(extra)], value))
+			}
+
+			if err != nil {
+				return fmt.Errorf("mapValues: value at key %v: %w", key, err)
+			}
+		}
+
+		return nil
+	})
+
+	RegisterRuleArity(validator, "notOneOf", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("notOneOf: expected at least 2 parameters, got %d", len(params))
+		}
+
+		subject, forbidden := params[0], params[1:]
+		for _, f := range forbidden {
+			if reflect.DeepEqual(subject, f) {
+				return fmt.Errorf("notOneOf: %v is not allowed", subject)
+			}
+		}
+
+		return nil
+	})
+
+	// required fails for nil and zero values only, so unlike notEmpty it
+	// accepts a non-nil pointer to a zero value, e.g. a *int pointing at 0.
+	// It sees pointers as they are, see rawParamRules.
+	RegisterRuleArity(validator, "required", 1, func(params []any) error {
+		for _, p := range params {
+			if p == nil || reflect.ValueOf(p).IsZero() {
+				return errors.New("is required")
+			}
+		}
+
+		return nil
+	})
+
+	// jsonRoundTrip fails for values that change when encoded to JSON and
+	// decoded into a new value of the same type, e.g. because of unexported
+	// fields or json:"-" tags. Times are compared as instants.
+	RegisterRuleArity(validator, "jsonRoundTrip", 1, func(params []any) error {
+		for _, p := range params {
+			if p == nil {
+				continue
+			}
+
+			data, err := json.Marshal(p)
+			if err != nil {
+				return fmt.Errorf("jsonRoundTrip: %T cannot be encoded as JSON: %w", p, err)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(p))
+			if err := json.Unmarshal(data, decoded.Interface()); err != nil {
+				return fmt.Errorf("jsonRoundTrip: %T cannot be decoded from its own JSON: %w", p, err)
+			}
+
+			if !jsonEqual(reflect.ValueOf(p), decoded.Elem()) {
+				return fmt.Errorf("jsonRoundTrip: %T changes when encoded to JSON and decoded again", p)
+			}
+		}
+
+		return nil
+	})
+
+	// allowedRunes takes the permitted characters first and requires the
+	// strings after it to use only those. Positions count runes from 1.
+	RegisterRuleArity(validator, "allowedRunes", 2, func(params []any) error {
+		if len(params) < 2 {
+			return SystemErrorf("allowedRunes: expected at least 2 parameters, got %d", len(params))
+		}
+
+		allowed, ok := params[0].(string)
+		if !ok {
+			return SystemErrorf("allowedRunes: unsupported type %T for allowed runes", params[0])
+		}
+
+		for _, p := range params[1:] {
+			str, ok := p.(string)
+			if !ok {
+				return SystemErrorf("allowedRunes: unsupported type %T", p)
+			}
+
+			pos := 0
+			for _, r := range str {
+				pos++
+				if !strings.ContainsRune(allowed, r) {
+					return fmt.Errorf("allowedRunes: %q at position %d is not allowed", r, pos)
+				}
+			}
+		}
+
+		return nil
+	})
+
+	// inBoundingBox checks that the point lat, lng lies inside the box
+	// minLat, minLng, maxLat, maxLng, edges included. A box whose minLng is
+	// greater than its maxLng crosses the antimeridian.
+	RegisterRuleArity(validator, "inBoundingBox", 6, func(params []any) error {
+		if err := checkParamCount("inBoundingBox", params, 6); err != nil {
+			return err
+		}
+
+		coords := make([]float64, len(params))
+		for i, p := range params {
+			m, err := measureNumber("inBoundingBox", p)
+			if err != nil {
+				return err
+			}
+			coords[i], _ = m.rat.Float64()
+		}
+
+		minLat, minLng, maxLat, maxLng, lat, lng := coords[0], coords[1], coords[2], coords[3], coords[4], coords[5]
+		if minLat < -90 || maxLat > 90 || minLat > maxLat {
+			return SystemErrorf("inBoundingBox: invalid latitude range [%v, %v]", params[0], params[2])
+		}
+		if minLng < -180 || minLng > 180 || maxLng < -180 || maxLng > 180 {
+			return SystemErrorf("inBoundingBox: invalid longitude range [%v, %v]", params[1], params[3])
+		}
+
+		inLng := lng >= minLng && lng <= maxLng
+		if minLng > maxLng {
+			inLng = lng >= minLng || lng <= maxLng
+		}
+
+		if lat < minLat || lat > maxLat || !inLng {
+			return valueErrorf("must be inside the bounding box", "inBoundingBox: (%v, %v) is outside the bounding box", params[4], params[5])
+		}
+
+		return nil
+	})
 }
 
-func RegisterRule(v *Validator, ruleName string, fnc RuleFunc) {
-	v.rules[ruleName] = fnc
+// rawParamRules are the built-ins that tell pointers apart from the values
+// they point to, so their params are not dereferenced.
+var rawParamRules = map[string]bool{
+	"required": true,
 }
 
-func RegisterType[T any](v *Validator, handler func(s T, ctx *ValidationContext)) {
-	v.typeHandlers[reflect.TypeFor[T]()] = func(a any, cc *ValidationContext) {
-		handler(a.(T), cc)
+// derefParams lets a built-in rule accept pointers by passing it the values
+// that non-nil pointer params point to. Compiled patterns are left alone.
+func derefParams(fnc RuleFunc) RuleFunc {
+	return func(params []any) error {
+		var derefed []any
+		for i, p := range params {
+			rv := reflect.ValueOf(p)
+			if rv.Kind() != reflect.Pointer || rv.IsNil() {
+				continue
+			}
+			if _, ok := p.(*regexp.Regexp); ok {
+				continue
+			}
+
+			if derefed == nil {
+				derefed = append([]any(nil), params...)
+			}
+			for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+				rv = rv.Elem()
+			}
+			derefed[i] = rv.Interface()
+		}
+
+		if derefed == nil {
+			return fnc(params)
+		}
+
+		return fnc(derefed)
 	}
 }
 
-func (ctx *ValidationContext) Message(message string) *ValidationContext {
-	if ctx.err != nil {
-		ctx.err = errors.New(message)
+// compilePattern compiles each pattern once per validator.
+func (v *Validator) compilePattern(pattern string) (*regexp.Regexp, error) {
+	if re, ok := v.patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
 	}
 
-	return ctx
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	v.patterns.Store(pattern, re)
+	return re, nil
 }
 
-func (ctx *ValidationContext) Check(handlerName string, params ...any) *ValidationContext {
-	if ctx.err != nil {
-		return ctx
+// lengthOf returns the length of a string in runes, or of a collection in
+// elements, along with the unit to report it in. Runes are counted so that
+// multibyte input is measured the way users see it.
+func lengthOf(ruleName string, value any) (int, string, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String()), "characters", nil
+	case reflect.Array, reflect.Slice, reflect.Map:
+		return rv.Len(), "elements", nil
+	default:
+		return 0, "", SystemErrorf("%s: unsupported type %T, expected a string, slice, array or map", ruleName, value)
 	}
+}
 
-	rule, ok := ctx.validator.rules[handlerName]
-	if !ok {
-		panic("Rule " + handlerName + " has not been registered to specified validator")
+// checkLength checks the length of the value in params[0] against the bound
+// in params[1], as ok reports.
+func checkLength(ruleName string, params []any, ok func(n, bound int) bool, relation string) error {
+	if len(params) != 2 {
+		return SystemErrorf("%s: expected a value and a length, got %d parameters", ruleName, len(params))
 	}
 
-	err := rule(params)
-	ctx.err = err
-	return ctx
+	bound, err := lengthBound(ruleName, params[1])
+	if err != nil {
+		return err
+	}
+
+	n, unit, err := lengthOf(ruleName, params[0])
+	if err != nil {
+		return err
+	}
+
+	if !ok(n, bound) {
+		return fmt.Errorf("must be %s %d %s, got %d", relation, bound, unit, n)
+	}
+
+	return nil
 }
 
-func (ctx *ValidationContext) Must(fnc func() bool) *ValidationContext {
-	if ctx.err != nil {
-		return ctx
+func lengthBound(ruleName string, bound any) (int, error) {
+	rv := reflect.ValueOf(bound)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return 0, SystemErrorf("%s: negative length %d", ruleName, rv.Int())
+		}
+		return int(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int(rv.Uint()), nil
+	default:
+		return 0, SystemErrorf("%s: unsupported type %T for length", ruleName, bound)
 	}
+}
 
-	if !fnc() {
-		ctx.err = errors.New("rule failed")
+func compareTimeField(ruleName string, params []any, ok func(subject, sibling time.Time) bool, relation string) error {
+	if len(params) < 3 {
+		return SystemErrorf("%s: expected at least 3 parameters, got %d", ruleName, len(params))
 	}
 
-	return ctx
+	name, isString := params[0].(string)
+	if !isString {
+		return SystemErrorf("%s: unsupported type %T for field name", ruleName, params[0])
+	}
+
+	sibling, present, err := timeOf(ruleName, params[1])
+	if err != nil || !present {
+		return err
+	}
+
+	for _, p := range params[2:] {
+		subject, present, err := timeOf(ruleName, p)
+		if err != nil {
+			return err
+		}
+
+		if present && !ok(subject, sibling) {
+			return fmt.Errorf("must be %s %s (%s)", relation, name, sibling.Format(time.RFC3339))
+		}
+	}
+
+	return nil
 }
 
-func ValidateStruct[T any](v *Validator, s T) error {
-	typ := reflect.TypeOf(s)
+// timeOf reads a time.Time, *time.Time or RFC3339 string. Nil pointers, zero
+// times and empty strings are reported as not present.
+func timeOf(ruleName string, value any) (time.Time, bool, error) {
+	switch t := value.(type) {
+	case nil:
+		return time.Time{}, false, nil
+	case time.Time:
+		return t, !t.IsZero(), nil
+	case *time.Time:
+		if t == nil {
+			return time.Time{}, false, nil
+		}
+		return *t, !t.IsZero(), nil
+	case string:
+		if t == "" {
+			return time.Time{}, false, nil
+		}
 
-	handler, ok := v.typeHandlers[typ]
-	if !ok {
-		panic("type " + typ.Name() + " hasn't been registered with RegisterType")
+		parsed, err := time.Parse(time.RFC3339, t)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("%s: %q is not an RFC3339 time", ruleName, t)
+		}
+		return parsed, true, nil
+	default:
+		return time.Time{}, false, SystemErrorf("%s: unsupported type %T, expected time.Time, *time.Time or string", ruleName, value)
+	}
+}
+
+// measured is the number a comparison rule compares: the value of numeric
+// kinds and the length of strings, slices, arrays and maps. It is kept as a
+// big.Rat so that large int64 and uint64 values compare exactly.
+type measured struct {
+	rat     *big.Rat
+	display any
+}
+
+func (m measured) cmp(other measured) int {
+	return m.rat.Cmp(other.rat)
+}
+
+func (m measured) String() string {
+	return fmt.Sprint(m.display)
+}
+
+func measure(ruleName string, value any) (measured, error) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return measured{new(big.Rat).SetInt64(int64(rv.Len())), rv.Len()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return measured{new(big.Rat).SetInt64(rv.Int()), value}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return measured{new(big.Rat).SetUint64(rv.Uint()), value}, nil
+	case reflect.Float32, reflect.Float64:
+		r := new(big.Rat).SetFloat64(rv.Float())
+		if r == nil {
+			return measured{}, fmt.Errorf("%s: %v is not a finite number", ruleName, value)
+		}
+		return measured{r, value}, nil
+	default:
+		return measured{}, SystemErrorf("%s: unsupported type %T", ruleName, value)
 	}
+}
 
-	ctx := ValidationContext{
-		validator: v,
+// measureNumber is measure restricted to numeric kinds.
+func measureNumber(ruleName string, value any) (measured, error) {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
+		return measured{}, SystemErrorf("%s: unsupported type %T, expected a number", ruleName, value)
 	}
-	handler(s, &ctx)
 
-	return ctx.err
+	return measure(ruleName, value)
 }
 
-func New() *Validator { //
-	validator := &Validator{
-		rules:        make(map[string]RuleFunc, 0),
-		typeHandlers: make(map[reflect.Type]HandlerFunc, 0),
+// compareToBound checks that every value after the bound in params relates
+// to it as ok reports for the result of comparing the value to the bound.
+func compareToBound(ruleName string, params []any, ok func(c int) bool, relation string) error {
+	if len(params) < 2 {
+		return SystemErrorf("%s: expected at least 2 parameters, got %d", ruleName, len(params))
 	}
-	RegisterRule(validator, "notEmpty", func(param []any) error {
-		for _, p := range param {
 
-			length := 0
-			rv := reflect.ValueOf(p)
-			kind := rv.Kind()
+	bound, err := measure(ruleName, params[0])
+	if err != nil {
+		return err
+	}
 
-			if kind == reflect.Array ||
-				kind == reflect.Slice ||
-				kind == reflect.Map {
-				length = rv.Len()
-			} else if kind == reflect.String {
-				length = len(strings.TrimSpace(p.(string)))
-			}
+	for i, p := range params[1:] {
+		val, err := measure(ruleName, p)
+		if err != nil {
+			return err
+		}
 
-			if length == 0 {
-				return errors.New("required rule failed")
+		if !ok(val.cmp(bound)) {
+			return fmt.Errorf(
+				"%s: parameter at position %d (= %v) is not %s %v",
+				ruleName, i+2, val, relation, bound,
+			)
+		}
+	}
+
+	return nil
+}
+
+// maxEmailLength is the longest address that fits in an SMTP path.
+const maxEmailLength = 254
+
+// validEmail parses email as an RFC 5322 address, which takes care of quoted
+// local parts and control characters, then rejects what a form field should
+// not contain: display names, angle brackets, comments and surrounding
+// space. The domain needs at least two non-empty dot-separated labels. In
+// strict mode the last label must be two or more letters.
+func validEmail(email string, strict bool) bool {
+	if len(email) > maxEmailLength || strings.TrimSpace(email) != email {
+		return false
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || strings.HasPrefix(email, "<") {
+		return false
+	}
+
+	at := strings.LastIndexByte(addr.Address, '@')
+	domain := addr.Address[at+1:]
+	if !strings.HasSuffix(email, "@"+domain) {
+		return false
+	}
+
+	if !strings.Contains(domain, ".") || !validDotAtoms(domain) {
+		return false
+	}
+
+	if strict {
+		tld := domain[strings.LastIndexByte(domain, '.')+1:]
+		if len(tld) < 2 {
+			return false
+		}
+		for _, r := range tld {
+			if !unicode.IsLetter(r) {
+				return false
 			}
 		}
+	}
+
+	return true
+}
+
+// validDotAtoms reports whether s is non-empty and has no empty parts between
+// dots, i.e. no leading, trailing or consecutive dots.
+func validDotAtoms(s string) bool {
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+	}
 
-		return nil
-	})
+	return true
+}
 
-	RegisterRule(validator, "greaterThan", func(params []any) error {
-		// need at least two args: one comparer + at least one to compare
-		if len(params) < 2 {
-			msg := fmt.Sprintf("greaterThan: expected at least 2 parameters, got %d", len(params))
-			panic(msg)
-		}
-
-		// --- determine the “comparer” from the first param ---
-		first := params[0]
-		rv := reflect.ValueOf(first)
-
-		var comparer float64
-		switch rv.Kind() {
-		case reflect.String, reflect.Array, reflect.Slice, reflect.Map:
-			comparer = float64(rv.Len())
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			comparer = float64(rv.Int())
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			comparer = float64(rv.Uint())
-		case reflect.Float32, reflect.Float64:
-			comparer = rv.Float()
+// validISBN10 checks the weighted mod 11 checksum; the last digit may be X.
+func validISBN10(isbn string) bool {
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := isbn[i]
+		var digit int
+		switch {
+		case c >= '0' && c <= '9':
+			digit = int(c - '0')
+		case (c == 'X' || c == 'x') && i == 9:
+			digit = 10
 		default:
-			return fmt.Errorf("greaterThan: unsupported type %T for comparer", first)
-		}
-
-		// --- for each of the remaining params, extract value/length and compare ---
-		for i, arg := range params[1:] {
-			rv := reflect.ValueOf(arg)
-
-			var val float64
-			switch rv.Kind() {
-			case reflect.String, reflect.Array, reflect.Slice:
-				val = float64(rv.Len())
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				val = float64(rv.Int())
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				val = float64(rv.Uint())
-			case reflect.Float32, reflect.Float64:
-				val = rv.Float()
-			default:
-				return fmt.Errorf(
-					"greaterThan: unsupported type %T at position %d",
-					arg, i+2,
-				)
-			}
+			return false
+		}
+		sum += digit * (10 - i)
+	}
+
+	return sum%11 == 0
+}
+
+// validISBN13 checks the EAN-13 checksum, weighting digits alternately 1 and 3.
+func validISBN13(isbn string) bool {
+	sum := 0
+	for i := 0; i < 13; i++ {
+		c := isbn[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(c-'0') * weight
+	}
+
+	return sum%10 == 0
+}
+
+func valuesEqual(a, b any) bool {
+	if isNumber(a) && isNumber(b) {
+		x, errX := measure("equals", a)
+		y, errY := measure("equals", b)
+		return errX == nil && errY == nil && x.cmp(y) == 0
+	}
 
-			if val <= comparer {
-				return fmt.Errorf(
-					"greaterThan: parameter at position %d (= %v) is not greater than %v",
-					i+2, val, comparer,
-				)
+	return reflect.DeepEqual(a, b)
+}
+
+// compareField requires every value after the sibling field's name and value
+// to equal the sibling's value, or to differ from it when equal is false.
+func compareField(ruleName string, params []any, equal bool) error {
+	if len(params) < 3 {
+		return SystemErrorf("%s: expected at least 3 parameters, got %d", ruleName, len(params))
+	}
+
+	name, ok := params[0].(string)
+	if !ok {
+		return SystemErrorf("%s: unsupported type %T for field name", ruleName, params[0])
+	}
+
+	for _, p := range params[2:] {
+		if valuesEqual(params[1], p) != equal {
+			if equal {
+				return fmt.Errorf("must equal %s", name)
 			}
+			return fmt.Errorf("must not equal %s", name)
 		}
+	}
 
-		return nil
-	})
+	return nil
+}
 
-	RegisterRule(validator, "lessThan", func(params []any) error {
-		// need at least two args: one comparer + at least one to compare
-		if len(params) < 2 {
-			msg := fmt.Sprintf("lessThan: expected at least 2 parameters, got %d", len(params))
-			panic(msg)
-		}
-
-		// --- determine the “comparer” from the first param ---
-		first := params[0]
-		rv := reflect.ValueOf(first)
-
-		var comparer float64
-		switch rv.Kind() {
-		case reflect.String, reflect.Array, reflect.Slice:
-			comparer = float64(rv.Len())
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			comparer = float64(rv.Int())
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-			comparer = float64(rv.Uint())
-		case reflect.Float32, reflect.Float64:
-			comparer = rv.Float()
-		default:
-			return fmt.Errorf("lessThan: unsupported type %T for comparer", first)
-		}
-
-		// --- for each of the remaining params, extract value/length and compare ---
-		for i, arg := range params[1:] {
-			rv := reflect.ValueOf(arg)
-
-			var val float64
-			switch rv.Kind() {
-			case reflect.String, reflect.Array, reflect.Slice:
-				val = float64(rv.Len())
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				val = float64(rv.Int())
-			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
-				val = float64(rv.Uint())
-			case reflect.Float32, reflect.Float64:
-				val = rv.Float()
-			default:
-				return fmt.Errorf(
-					"lessThan: unsupported type %T at position %d",
-					arg, i+2,
-				)
+func isNumber(value any) bool {
+	switch reflect.ValueOf(value).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// compareOrdered compares times or numbers with the reference value in
+// params[0], requiring ok to hold for the result of comparing each value to
+// it.
+func compareOrdered(ruleName string, params []any, ok func(c int) bool, relation string) error {
+	if len(params) < 2 {
+		return SystemErrorf("%s: expected at least 2 parameters, got %d", ruleName, len(params))
+	}
+
+	if ref, isTime := params[0].(time.Time); isTime {
+		for _, p := range params[1:] {
+			t, isTime := p.(time.Time)
+			if !isTime {
+				return SystemErrorf("%s: cannot compare %T with time.Time", ruleName, p)
 			}
 
-			if val >= comparer {
-				return fmt.Errorf(
-					"lessThan: parameter at position %d (= %v) is not less than %v",
-					i+2, val, comparer,
-				)
+			if !ok(t.Compare(ref)) {
+				return valueErrorf("must be "+relation+" the given time", "%s: %s is not %s %s", ruleName, t.Format(time.RFC3339), relation, ref.Format(time.RFC3339))
 			}
 		}
 
 		return nil
-	})
+	}
 
-	RegisterRule(validator, "isEmail", func(param []any) error {
-		if len(param) == 0 {
-			panic("No parameters passed to email rule")
+	ref, err := measureNumber(ruleName, params[0])
+	if err != nil {
+		return err
+	}
+
+	for _, p := range params[1:] {
+		val, err := measureNumber(ruleName, p)
+		if err != nil {
+			return err
 		}
 
-		email, ok := param[0].(string)
-		if !ok {
-			panic("Parameter passed to email rule is not a string")
+		if !ok(val.cmp(ref)) {
+			return valueErrorf("must be "+relation+" the given value", "%s: %v is not %s %v", ruleName, val, relation, ref)
 		}
+	}
 
-		var ampIsThere bool
-		var spacesThere bool
-		var textBeforeAmp bool
-		var textAfterAmp bool
-		var dotAfterAmp bool
-		var otherError bool
+	return nil
+}
 
-		for _, r := range email {
-			if r == '@' {
-				if ampIsThere {
-					otherError = true
-				}
+// withNow resolves the reference time of before and after: a leading "now"
+// is replaced with the current time, which is also used when the only param
+// is the value being compared.
+func (v *Validator) withNow(params []any) []any {
+	if len(params) == 1 {
+		return []any{v.now(), params[0]}
+	}
 
-				ampIsThere = true
-			} else if !ampIsThere {
-				textBeforeAmp = true
-			} else if r == '.' {
-				dotAfterAmp = true
-			} else {
-				textAfterAmp = true
-			}
+	if len(params) > 1 && params[0] == "now" {
+		return append([]any{v.now()}, params[1:]...)
+	}
 
-			if r == ' ' || r == ',' {
-				spacesThere = true
+	return params
+}
+
+// checkSequence requires ok to hold for the comparison of every param with
+// the one before it, reporting the first pair that breaks the order. A single
+// slice or array param is checked element by element, so the rules also work
+// as tags on slice fields.
+func checkSequence(ruleName string, params []any, ok func(c int) bool, relation string) error {
+	if len(params) == 1 {
+		rv := reflect.ValueOf(params[0])
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			params = make([]any, rv.Len())
+			for i := range params {
+				params[i] = rv.Index(i).Interface()
 			}
 		}
+	}
 
-		if spacesThere ||
-			!ampIsThere ||
-			!textAfterAmp ||
-			!textBeforeAmp ||
-			!dotAfterAmp ||
-			otherError {
-			return errors.New("email addresses must be valid, working, and must have no commas or spaces")
+	var prev measured
+	for i, p := range params {
+		val, err := measureNumber(ruleName, p)
+		if err != nil {
+			return err
 		}
 
-		return nil
-	})
+		if i > 0 && !ok(val.cmp(prev)) {
+			return fmt.Errorf(
+				"%s: parameter at position %d (= %v) is not %s the one before it (= %v)",
+				ruleName, i+1, val, relation, prev,
+			)
+		}
+		prev = val
+	}
 
-	return validator
+	return nil
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// jsonEqual is reflect.DeepEqual for jsonRoundTrip, except that times are
+// equal when they are the same instant: JSON keeps neither the monotonic
+// clock reading nor the location. a and b have the same type.
+func jsonEqual(a, b reflect.Value) bool {
+	if a.Type() == timeType && a.CanInterface() {
+		return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Kind() == reflect.Interface && a.Elem().Type() != b.Elem().Type() {
+			return false
+		}
+		return jsonEqual(a.Elem(), b.Elem())
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !jsonEqual(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if a.Kind() == reflect.Slice && a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !jsonEqual(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.MapKeys() {
+			other := b.MapIndex(key)
+			if !other.IsValid() || !jsonEqual(a.MapIndex(key), other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	case reflect.String:
+		return a.String() == b.String()
+	default:
+		// like reflect.DeepEqual, funcs and channels are only equal when nil
+		return a.IsNil() && b.IsNil()
+	}
 }
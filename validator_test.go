@@ -0,0 +1,1666 @@
+package validator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type allFieldsProfile struct {
+	_     struct{} `validate:"allFields=notEmpty"`
+	First string
+	Last  string
+	Email string
+	Age   int
+}
+
+type allFieldsContact struct {
+	_       struct{} `validate:"allFields=isEmail"`
+	Primary string
+	Backup  string
+	Count   int
+	Tags    []string
+	hidden  string
+}
+
+type allFieldsOuter struct {
+	A     string `validate:"notEmpty"`
+	Inner allFieldsProfile
+	B     string `validate:"notEmpty"`
+}
+
+func TestAllFieldsDirective(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		value   any
+		want    []string
+	}{
+		{"all set", nil, allFieldsProfile{First: "Ada", Last: "Lovelace", Email: "ada@example.com"}, nil},
+		{"one empty", nil, allFieldsProfile{First: "Ada", Email: "ada@example.com"}, []string{"Last"}},
+		{"each empty field reported", nil, allFieldsProfile{Last: "Lovelace"}, []string{"First", "Email"}},
+		{"nested stops at first failure", nil, allFieldsOuter{Inner: allFieldsProfile{First: "Ada"}}, []string{"A"}},
+		{"nested reports its fields", nil, allFieldsOuter{A: "a", Inner: allFieldsProfile{First: "Ada"}}, []string{"Inner.Last", "Inner.Email"}},
+		{"nested with collect all", []Option{WithCollectAll()}, allFieldsOuter{Inner: allFieldsProfile{First: "Ada"}}, []string{"A", "Inner.Last", "Inner.Email", "B"}},
+		{"incompatible kinds skipped", []Option{WithCollectAll()}, allFieldsContact{Primary: "a@example.com", Backup: "nope", Tags: []string{"x"}, hidden: "x"}, []string{"Backup"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedFields(New(tt.options...).ValidateStruct(tt.value))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type roundTripSafe struct {
+	Name string
+	Tags []string
+	At   time.Time
+	Meta map[string]any
+}
+
+type roundTripUnexported struct {
+	Name   string
+	secret string
+}
+
+type roundTripSkipped struct {
+	Name     string
+	Password string `json:"-"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr bool
+	}{
+		{"safe struct", roundTripSafe{Name: "a", Tags: []string{"x"}, Meta: map[string]any{"n": 1.5}}, false},
+		{"timestamp", roundTripSafe{At: time.Now()}, false},
+		{"timestamp in another zone", roundTripSafe{At: time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 7200))}, false},
+		{"pointer to timestamp", &struct{ At *time.Time }{At: ptrTo(time.Now())}, false},
+		{"unexported field lost", roundTripUnexported{Name: "a", secret: "s"}, true},
+		{"unexported zero value", roundTripUnexported{Name: "a"}, false},
+		{"json dash field lost", roundTripSkipped{Password: "p"}, true},
+		{"int becomes float in any", map[string]any{"n": 1}, true},
+		{"nil", nil, false},
+		{"nil slice becomes null", roundTripSafe{Tags: nil}, false},
+		{"channel cannot be encoded", make(chan int), true},
+		{"func field cannot be encoded", struct{ F func() }{F: func() {}}, true},
+		{"NaN cannot be encoded", math.NaN(), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("jsonRoundTrip", tt.value).Err()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("jsonRoundTrip(%v) = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := Check("jsonRoundTrip", roundTripSafe{Name: "a"}, roundTripUnexported{secret: "s"}).Err(); err == nil {
+		t.Error("jsonRoundTrip did not check every parameter")
+	}
+}
+
+func ptrTo[T any](v T) *T {
+	return &v
+}
+
+func TestLengthRules(t *testing.T) {
+	tests := []struct {
+		rule      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"minLength", []any{"hunter22", 8}, false, UserError},
+		{"minLength", []any{"hunter2", 8}, true, UserError},
+		{"minLength", []any{"héllo", 5}, false, UserError},
+		{"minLength", []any{[]int{1, 2}, 3}, true, UserError},
+		{"maxLength", []any{"ada", 32}, false, UserError},
+		{"maxLength", []any{"日本語", 2}, true, UserError},
+		{"maxLength", []any{map[string]int{"a": 1}, 1}, false, UserError},
+		{"exactLength", []any{"123456", 6}, false, UserError},
+		{"exactLength", []any{"12345", 6}, true, UserError},
+		{"exactLength", []any{[3]int{}, 3}, false, UserError},
+		{"minLength", []any{8, "hunter22"}, true, SystemError},
+		{"minLength", []any{"abc", -1}, true, SystemError},
+		{"maxLength", []any{42, 2}, true, SystemError},
+		{"exactLength", []any{"a", "b", 1}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		err := Check(tt.rule, tt.params...).Err()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s(%v) = %v, wantErr %v", tt.rule, tt.params, err, tt.wantErr)
+			continue
+		}
+		if err != nil && Classify(err) != tt.wantClass {
+			t.Errorf("%s(%v) classified as %v, want %v", tt.rule, tt.params, Classify(err), tt.wantClass)
+		}
+	}
+}
+
+func TestLengthRuleMessage(t *testing.T) {
+	err := Check("minLength", "héllo", 8).Err()
+	if want := "must be at least 8 characters, got 5"; err == nil || err.Error() != want {
+		t.Errorf("got %v, want %q", err, want)
+	}
+}
+
+type lengthTagged struct {
+	Code string   `validate:"exactLength=6"`
+	Tags []string `validate:"maxLength=2,dive,minLength=2"`
+}
+
+func TestLengthRuleTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value lengthTagged
+		want  []string
+	}{
+		{"valid", lengthTagged{Code: "123456", Tags: []string{"go", "js"}}, nil},
+		{"short code", lengthTagged{Code: "123", Tags: []string{"go"}}, []string{"Code"}},
+		{"too many tags", lengthTagged{Code: "123456", Tags: []string{"go", "js", "py"}}, []string{"Tags"}},
+		{"short tag", lengthTagged{Code: "123456", Tags: []string{"go", "c"}}, []string{"Tags[1]"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedFields(New(WithCollectAll()).ValidateStruct(tt.value))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+			if errs := New().CheckStructTags(tt.value); len(errs) > 0 {
+				t.Errorf("CheckStructTags() = %v", errs)
+			}
+		})
+	}
+}
+
+type genericUntagged struct {
+	Name string
+}
+
+func TestGenericValidateStruct(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(v *Validator) error
+		wantErr  bool
+		want     Classification
+	}{
+		{"valid", func(v *Validator) error { return ValidateStruct(v, lengthTagged{Code: "123456"}) }, false, UserError},
+		{"invalid", func(v *Validator) error { return ValidateStruct(v, lengthTagged{Code: "123"}) }, true, UserError},
+		{"nil pointer", func(v *Validator) error { return ValidateStruct[*lengthTagged](v, nil) }, true, UserError},
+		{"nil interface", func(v *Validator) error { return ValidateStruct[any](v, nil) }, true, SystemError},
+		{"unregistered", func(v *Validator) error { return ValidateStruct(v, genericUntagged{}) }, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		for _, v := range []*Validator{New(), New(WithStrict())} {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.validate(v)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ValidateStruct() = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err != nil && Classify(err) != tt.want {
+					t.Errorf("ValidateStruct() = %v, want a %v", err, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestCheckArity(t *testing.T) {
+	v := New()
+	called := false
+	RegisterRuleArity(v, "inRange", 3, func(params []any) error {
+		called = true
+		return nil
+	})
+	RegisterRule(v, "anything", func(params []any) error { return nil })
+
+	tests := []struct {
+		name    string
+		rule    string
+		n       int
+		wantErr bool
+	}{
+		{"too few", "inRange", 2, true},
+		{"exact", "inRange", 3, false},
+		{"more", "inRange", 4, false},
+		{"no declared arity", "anything", 0, false},
+		{"unknown rule", "noSuchRule", 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := v.CheckArity(tt.rule, tt.n); (err != nil) != tt.wantErr {
+				t.Errorf("CheckArity(%q, %d) = %v, wantErr %v", tt.rule, tt.n, err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("check with too few", func(t *testing.T) {
+		ctx := v.newContext()
+		err := ctx.Check("inRange", 1, 2).Err()
+		if err == nil || Classify(err) != SystemError {
+			t.Errorf("Check() = %v, want a system error", err)
+		}
+		if called {
+			t.Error("the rule ran with too few parameters")
+		}
+	})
+}
+
+func TestJSONSafeNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"safe integer", json.Number("9007199254740992"), false, UserError},
+		{"beyond 2^53", json.Number("9007199254740993"), true, UserError},
+		{"exact fraction", json.Number("0.5"), false, UserError},
+		{"inexact fraction", json.Number("0.1"), true, UserError},
+		{"exponent", json.Number("1e3"), false, UserError},
+		{"raw string", "42", false, UserError},
+		{"raw bytes", []byte("18446744073709551616"), true, UserError},
+		{"not a number", "forty-two", true, UserError},
+		{"unsupported type", 42, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("jsonSafeNumber", tt.value).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("jsonSafeNumber(%v) = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%v classified as %v, want %v", err, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+type fatalShape struct {
+	Kind   string
+	Radius int `validate:"greaterThan=0"`
+}
+
+type fatalOuter struct {
+	Shape fatalShape
+	Name  string `validate:"notEmpty"`
+}
+
+func TestFatal(t *testing.T) {
+	tests := []struct {
+		name      string
+		options   []Option
+		value     any
+		wantFatal bool
+		want      []string
+	}{
+		{"valid", nil, fatalShape{Kind: "circle", Radius: 1}, false, nil},
+		{"ordinary failure", nil, fatalShape{Kind: "circle"}, false, []string{"Radius"}},
+		{"fatal halts", nil, fatalShape{Kind: "blob"}, true, []string{"Kind"}},
+		{"fatal halts collect all", []Option{WithCollectAll()}, fatalShape{Kind: "blob"}, true, []string{"Kind"}},
+		{"fatal halts outer walk", []Option{WithCollectAll()}, fatalOuter{Shape: fatalShape{Kind: "blob"}}, true, []string{"Shape.Kind"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(tt.options...)
+			later := 0
+			RegisterType(v, func(s fatalShape, ctx *ValidationContext) {
+				ctx.Field("Kind").Must(func() bool { return s.Kind != "" })
+				if s.Kind != "circle" {
+					ctx.Fatal(errors.New("unknown kind"))
+				}
+				ctx.Field("Radius").Must(func() bool { later++; return true })
+			})
+
+			err := v.ValidateStruct(tt.value)
+			var fatal *FatalError
+			if errors.As(err, &fatal) != tt.wantFatal {
+				t.Errorf("ValidateStruct() = %v, want fatal %v", err, tt.wantFatal)
+			}
+			if tt.wantFatal && later > 0 {
+				t.Error("checks after Fatal ran")
+			}
+			if got := failedFields(err); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type allowlistOrder struct {
+	SKU string `validate:"knownSKU"`
+}
+
+func TestAllowlistRule(t *testing.T) {
+	v := New()
+	lookups := 0
+	RegisterAllowlistRule(v, "knownSKU", func(value string) (bool, error) {
+		lookups++
+		if value == "outage" {
+			return false, errors.New("store unavailable")
+		}
+
+		return value == "SKU-1" || value == "SKU-2", nil
+	})
+	if lookups != 0 {
+		t.Fatalf("the loader ran %d times on registration", lookups)
+	}
+
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"allowed", []any{"SKU-1"}, false, UserError},
+		{"all allowed", []any{"SKU-1", "SKU-2"}, false, UserError},
+		{"rejected", []any{"SKU-3"}, true, UserError},
+		{"one rejected", []any{"SKU-1", "SKU-3"}, true, UserError},
+		{"loader error", []any{"outage"}, true, SystemError},
+		{"not a string", []any{42}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check("knownSKU", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("knownSKU(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%v classified as %v, want %v", err, Classify(err), tt.wantClass)
+			}
+		})
+	}
+
+	t.Run("tag", func(t *testing.T) {
+		if got := failedFields(v.ValidateStruct(allowlistOrder{SKU: "SKU-9"})); !slices.Equal(got, []string{"SKU"}) {
+			t.Errorf("failed fields = %v, want [SKU]", got)
+		}
+	})
+}
+
+func TestBalancedBrackets(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		wantErr string
+	}{
+		{"empty", "", ""},
+		{"no brackets", "a + b", ""},
+		{"balanced", "f(x[1], {y: (2)})", ""},
+		{"unexpected closer", "a)", "balancedBrackets: unexpected ')' at index 1"},
+		{"never closed", "(a", "balancedBrackets: '(' at index 0 is never closed"},
+		{"mismatched type", "[a)", "balancedBrackets: ')' at index 2 does not close '[' at index 0"},
+		{"counts runes", "é(]", "balancedBrackets: ']' at index 2 does not close '(' at index 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("balancedBrackets", tt.value).Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("balancedBrackets(%q) = %v", tt.value, err)
+				}
+				return
+			}
+
+			var ruleErr *RuleError
+			if !errors.As(err, &ruleErr) || ruleErr.Err.Error() != tt.wantErr {
+				t.Errorf("balancedBrackets(%q) = %v, want %q", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := Check("balancedBrackets", 42).Err(); err == nil || Classify(err) != SystemError {
+		t.Errorf("balancedBrackets(42) = %v, want a system error", err)
+	}
+}
+
+func TestConcurrentRegisterAndCheck(t *testing.T) {
+	v := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				RegisterRule(v, fmt.Sprintf("rule%d_%d", i, j), func(params []any) error { return nil })
+				RegisterType(v, func(s sharedCity, ctx *ValidationContext) {})
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				ctx := v.newContext()
+				if err := ctx.Check("greaterThan", 1, 2).Err(); err != nil {
+					t.Error(err)
+				}
+				v.HasRule(fmt.Sprintf("rule%d_%d", i, j))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		if !v.HasRule(fmt.Sprintf("rule%d_49", i)) {
+			t.Errorf("rule%d_49 was not registered", i)
+		}
+	}
+}
+
+func TestBuiltinRules(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier, later := now.Add(-time.Hour), now.Add(time.Hour)
+	digest := sha256.Sum256([]byte("s3cret"))
+	hash := "sha256:" + hex.EncodeToString(digest[:])
+
+	v := New(WithClock(func() time.Time { return now }))
+	RegisterEnum(v, "color", "red", "green")
+
+	tests := []struct {
+		rule    string
+		params  []any
+		wantErr bool
+	}{
+		{"notEmpty", []any{"x"}, false},
+		{"notEmpty", []any{"  "}, true},
+		{"greaterThan", []any{17, 18}, false},
+		{"greaterThan", []any{17, 17}, true},
+		{"lessThan", []any{10, 9.5}, false},
+		{"lessThan", []any{10, 10}, true},
+		{"greaterOrEqual", []any{17, 17}, false},
+		{"greaterOrEqual", []any{17, 16}, true},
+		{"lessOrEqual", []any{10, 10}, false},
+		{"lessOrEqual", []any{10, 11}, true},
+		{"isEmail", []any{"ada@example.com"}, false},
+		{"isEmail", []any{"ada"}, true},
+		{"jsonSafeNumber", []any{json.Number("42")}, false},
+		{"jsonSafeNumber", []any{json.Number("9007199254740993")}, true},
+		{"balancedBrackets", []any{"(a[b])"}, false},
+		{"balancedBrackets", []any{"(a]"}, true},
+		{"regex", []any{"abc", "^[a-c]+$"}, false},
+		{"regex", []any{"abd", "^[a-c]+$"}, true},
+		{"oneOf", []any{"b", "a", "b"}, false},
+		{"oneOf", []any{"c", "a", "b"}, true},
+		{"notOneOf", []any{"c", "a", "b"}, false},
+		{"notOneOf", []any{"a", "a", "b"}, true},
+		{"minLength", []any{"abc", 3}, false},
+		{"minLength", []any{"ab", 3}, true},
+		{"maxLength", []any{"abc", 3}, false},
+		{"maxLength", []any{"abcd", 3}, true},
+		{"exactLength", []any{"abc", 3}, false},
+		{"exactLength", []any{"ab", 3}, true},
+		{"lengthBetween", []any{2, 4, "abc"}, false},
+		{"lengthBetween", []any{2, 4, "abcde"}, true},
+		{"between", []any{1, 10, 10}, false},
+		{"between", []any{1, 10, 11}, true},
+		{"afterField", []any{"Start", earlier, now}, false},
+		{"afterField", []any{"Start", later, now}, true},
+		{"beforeField", []any{"End", later, now}, false},
+		{"beforeField", []any{"End", earlier, now}, true},
+		{"isbn", []any{"978-0-306-40615-7"}, false},
+		{"isbn", []any{"978-0-306-40615-8"}, true},
+		{"multipleOfField", []any{"Step", 5, 15}, false},
+		{"multipleOfField", []any{"Step", 5, 16}, true},
+		{"isPercentEncoded", []any{"a%20b"}, false},
+		{"isPercentEncoded", []any{"a%2"}, true},
+		{"equals", []any{3, 3.0}, false},
+		{"equals", []any{3, 4}, true},
+		{"notEquals", []any{3, 4}, false},
+		{"notEquals", []any{3, 3}, true},
+		{"before", []any{earlier}, false},
+		{"before", []any{later}, true},
+		{"after", []any{later}, false},
+		{"after", []any{earlier}, true},
+		{"increasing", []any{[]int{1, 2, 3}}, false},
+		{"increasing", []any{[]int{1, 1, 2}}, true},
+		{"nonDecreasing", []any{[]int{1, 1, 2}}, false},
+		{"nonDecreasing", []any{[]int{2, 1}}, true},
+		{"matches", []any{"^a", "abc"}, false},
+		{"matches", []any{"^a", "bc"}, true},
+		{"eqField", []any{"Password", "pw", "pw"}, false},
+		{"eqField", []any{"Password", "pw", "other"}, true},
+		{"neField", []any{"Old", "pw", "other"}, false},
+		{"neField", []any{"Old", "pw", "pw"}, true},
+		{"mapValues", []any{map[string]string{"a": "x"}, "notEmpty"}, false},
+		{"mapValues", []any{map[string]string{"a": ""}, "notEmpty"}, true},
+		{"required", []any{ptrTo(0)}, false},
+		{"required", []any{0}, true},
+		{"jsonRoundTrip", []any{roundTripSafe{Name: "x"}}, false},
+		{"jsonRoundTrip", []any{roundTripUnexported{secret: "x"}}, true},
+		{"allowedRunes", []any{"abc", "cab"}, false},
+		{"allowedRunes", []any{"abc", "cad"}, true},
+		{"inBoundingBox", []any{0, 0, 10, 10, 5, 5}, false},
+		{"inBoundingBox", []any{0, 0, 10, 10, 11, 5}, true},
+		{"coversEnum", []any{map[string]int{"red": 1, "green": 2}, "color"}, false},
+		{"coversEnum", []any{map[string]int{"red": 1}, "color"}, true},
+		{"format", []any{"uuid", "123e4567-e89b-12d3-a456-426614174000"}, false},
+		{"format", []any{"uuid", "123e4567"}, true},
+		{"isUUID", []any{"123e4567-e89b-12d3-a456-426614174000"}, false},
+		{"isUUID", []any{"not-a-uuid"}, true},
+		{"isURL", []any{"https://example.com"}, false},
+		{"isURL", []any{"ftp://example.com"}, true},
+		{"alphanumeric", []any{"abc123"}, false},
+		{"alphanumeric", []any{"abc-123"}, true},
+		{"numericString", []any{"0123"}, false},
+		{"numericString", []any{"12a"}, true},
+		{"isNumeric", []any{"123"}, false},
+		{"isNumeric", []any{"12.a"}, true},
+		{"isAlpha", []any{"abc"}, false},
+		{"isAlpha", []any{"ab1"}, true},
+		{"isAlphanumeric", []any{"ab1"}, false},
+		{"isAlphanumeric", []any{"ab_1"}, true},
+		{"measurement", []any{"C", 20}, false},
+		{"measurement", []any{"C", -300}, true},
+		{"matchesHash", []any{"s3cret", hash}, false},
+		{"matchesHash", []any{"guess", hash}, true},
+	}
+
+	covered := make(map[string]bool)
+	for _, tt := range tests {
+		covered[tt.rule] = true
+		name := tt.rule + "/pass"
+		if tt.wantErr {
+			name = tt.rule + "/fail"
+		}
+
+		t.Run(name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Check(tt.rule, tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("%s(%v) = %v, wantErr %v", tt.rule, tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != UserError {
+				t.Errorf("%s(%v) = %v, want a user error", tt.rule, tt.params, err)
+			}
+		})
+	}
+
+	for name := range New().rules {
+		if !covered[name] {
+			t.Errorf("built-in rule %s is not tested", name)
+		}
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	custom := func(params []any) error { return errors.New("custom") }
+
+	tests := []struct {
+		name     string
+		options  []Option
+		rule     string
+		wantRule bool
+		wantErr  bool
+	}{
+		{"builtins", nil, "notEmpty", true, false},
+		{"without builtins", []Option{WithoutBuiltins()}, "notEmpty", false, false},
+		{"seeded rule", []Option{WithRules(map[string]RuleFunc{"custom": custom})}, "custom", true, true},
+		{"seeded without builtins", []Option{WithoutBuiltins(), WithRules(map[string]RuleFunc{"custom": custom})}, "custom", true, true},
+		{"seeded replaces builtin", []Option{WithRules(map[string]RuleFunc{"notEmpty": custom})}, "notEmpty", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(tt.options...)
+			if v.HasRule(tt.rule) != tt.wantRule {
+				t.Fatalf("HasRule(%q) = %v, want %v", tt.rule, !tt.wantRule, tt.wantRule)
+			}
+			if !tt.wantRule {
+				return
+			}
+
+			ctx := v.newContext()
+			if err := ctx.Check(tt.rule, "x").Err(); (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type genericAccount struct {
+	Owner string
+}
+
+type genericNamed interface {
+	Name() string
+}
+
+func (a genericAccount) Name() string { return a.Owner }
+
+func TestGenericValidate(t *testing.T) {
+	v := New()
+	RegisterType(v, func(a genericAccount, ctx *ValidationContext) {
+		ctx.Field("Owner").Check("notEmpty", a.Owner)
+	})
+
+	tests := []struct {
+		name      string
+		validate  func() error
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"valid", func() error { return Validate(v, genericAccount{Owner: "ada"}) }, false, UserError},
+		{"invalid", func() error { return Validate(v, genericAccount{}) }, true, UserError},
+		{"pointer", func() error { return Validate(v, &genericAccount{}) }, true, UserError},
+		{"nil pointer", func() error { return Validate[*genericAccount](v, nil) }, true, UserError},
+		{"interface", func() error { return Validate[genericNamed](v, genericAccount{}) }, true, UserError},
+		{"nil interface", func() error { return Validate[any](v, nil) }, true, SystemError},
+		{"no handler", func() error { return Validate(v, genericUntagged{}) }, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("%v classified as %v, want %v", err, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestRegisterWhileValidating(t *testing.T) {
+	v := New()
+	RegisterType(v, func(a genericAccount, ctx *ValidationContext) {
+		ctx.Field("Owner").Check("notEmpty", a.Owner)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			RegisterRule(v, fmt.Sprintf("lazy%d", i), func(params []any) error { return nil })
+			RegisterType(v, func(c sharedCity, ctx *ValidationContext) {})
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if err := Validate(v, genericAccount{Owner: "ada"}); err != nil {
+					t.Error(err)
+				}
+				if err := v.ValidateStruct(sharedCity{}); err == nil {
+					t.Error("ValidateStruct() accepted an empty city")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	<-done
+}
+
+func TestCompareKinds(t *testing.T) {
+	two := map[string]int{"a": 1, "b": 2}
+
+	tests := []struct {
+		name  string
+		bound any
+		value any
+		// greater and less are whether value is greater or less than bound
+		greater bool
+		less    bool
+	}{
+		{"int int", 2, 3, true, false},
+		{"float int", 2.5, 2, false, true},
+		{"int string", 2, "abc", true, false},
+		{"int slice", 2, []int{1}, false, true},
+		{"int array", 2, [3]int{}, true, false},
+		{"int map", 2, two, false, false},
+		{"map int", two, 3, true, false},
+		{"map map", two, map[string]int{"a": 1}, false, true},
+		{"array map", [1]int{}, two, true, false},
+		{"slice array", []int{1, 2, 3}, [2]int{}, false, true},
+		{"string map", "abc", two, false, true},
+		{"map string", two, "abc", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct {
+				rule string
+				want bool
+			}{{"greaterThan", tt.greater}, {"lessThan", tt.less}} {
+				err := Check(c.rule, tt.bound, tt.value).Err()
+				if err != nil && Classify(err) != UserError {
+					t.Fatalf("%s(%v, %v) = %v", c.rule, tt.bound, tt.value, err)
+				}
+				if got := err == nil; got != c.want {
+					t.Errorf("%s(%v, %v) passed = %v, want %v", c.rule, tt.bound, tt.value, got, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestByField(t *testing.T) {
+	rulesOf := func(errs []error) []string {
+		var rules []string
+		for _, err := range errs {
+			var ruleErr *RuleError
+			if errors.As(err, &ruleErr) {
+				rules = append(rules, ruleErr.Rule)
+			}
+		}
+		return rules
+	}
+
+	tests := []struct {
+		name  string
+		check func(ctx *ValidationContext)
+		want  map[string][]string
+	}{
+		{"no failures", func(ctx *ValidationContext) {
+			ctx.Field("Name").Check("notEmpty", "ada")
+		}, map[string][]string{}},
+		{"two fields, two rules each", func(ctx *ValidationContext) {
+			ctx.Field("Name").Check("notEmpty", "").Check("minLength", "", 2)
+			ctx.Field("Age").Check("greaterThan", 17, 3).Check("lessThan", 2, 3)
+			ctx.Field("City").Check("notEmpty", "Paris")
+		}, map[string][]string{"Name": {"notEmpty", "minLength"}, "Age": {"greaterThan", "lessThan"}}},
+		{"interleaved", func(ctx *ValidationContext) {
+			ctx.Field("Name").Check("notEmpty", "")
+			ctx.Field("Age").Check("greaterThan", 17, 3)
+			ctx.Field("Name").Check("minLength", "", 2)
+		}, map[string][]string{"Name": {"notEmpty", "minLength"}, "Age": {"greaterThan"}}},
+		{"no field", func(ctx *ValidationContext) {
+			ctx.Check("notEmpty", "")
+		}, map[string][]string{"": {"notEmpty"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New(WithCollectAll()).newContext()
+			tt.check(&ctx)
+
+			grouped := ctx.ByField()
+			got := make(map[string][]string, len(grouped))
+			for field, errs := range grouped {
+				got[field] = rulesOf(errs)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ByField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsEmail(t *testing.T) {
+	tests := []struct {
+		email  string
+		loose  bool
+		strict bool
+	}{
+		{"ada@example.com", true, true},
+		{"first.last+tag@example.com", true, true},
+		{"x@sub.example.org", true, true},
+		{"a@b.c", true, false},
+		{"a@b.c1", true, false},
+		{"a@b.", false, false},
+		{"@.x", false, false},
+		{"@example.com", false, false},
+		{"a@.example.com", false, false},
+		{".a@example.com", false, false},
+		{"a.@example.com", false, false},
+		{"a..b@example.com", false, false},
+		{"a@example..com", false, false},
+		{"a@example", false, false},
+		{"a@", false, false},
+		{"a", false, false},
+		{"", false, false},
+		{" ada@example.com", false, false},
+		{"a b@example.com", false, false},
+		{"Ada <ada@example.com>", false, false},
+		{"<ada@example.com>", false, false},
+		{"a@b@example.com", false, false},
+		{`"john doe"@example.com`, true, true},
+		{`"a@b"@example.com`, true, true},
+		{"o'brien@example.ie", true, true},
+		{"user_name-1@mail.example.co.uk", true, true},
+		{"UPPER@EXAMPLE.COM", true, true},
+		{"a@b.c.", false, false},
+		{"user@com.", false, false},
+		{"user@example.com.", false, false},
+		{"a\x01@example.com", false, false},
+		{"a\n@example.com", false, false},
+		{"ada@example.com\n", false, false},
+		{strings.Repeat("a", 64) + "@" + strings.Repeat("b", 185) + ".com", true, true},
+		{strings.Repeat("a", 64) + "@" + strings.Repeat("b", 186) + ".com", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if err := Check("isEmail", tt.email).Err(); (err == nil) != tt.loose {
+				t.Errorf("isEmail(%q) = %v, want valid %v", tt.email, err, tt.loose)
+			}
+			if err := Check("isEmail", "strict", tt.email).Err(); (err == nil) != tt.strict {
+				t.Errorf("isEmail(strict, %q) = %v, want valid %v", tt.email, err, tt.strict)
+			}
+			if err := Check("isEmail", "loose", tt.email).Err(); (err == nil) != tt.loose {
+				t.Errorf("isEmail(loose, %q) = %v, want valid %v", tt.email, err, tt.loose)
+			}
+		})
+	}
+
+	for _, params := range [][]any{{"fuzzy", "ada@example.com"}, {42}} {
+		if err := Check("isEmail", params...).Err(); err == nil || Classify(err) != SystemError {
+			t.Errorf("isEmail(%v) = %v, want a system error", params, err)
+		}
+	}
+}
+
+func TestISBN(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"isbn-10", []any{"0-306-40615-2"}, false, UserError},
+		{"isbn-10 with X", []any{"0-8044-2957-X"}, false, UserError},
+		{"isbn-10 without hyphens", []any{"0306406152"}, false, UserError},
+		{"isbn-10 bad checksum", []any{"0-306-40615-3"}, true, UserError},
+		{"isbn-13", []any{"978-0-306-40615-7"}, false, UserError},
+		{"isbn-13 bad checksum", []any{"978-0-306-40615-8"}, true, UserError},
+		{"X in isbn-13", []any{"978-0-306-40615-X"}, true, UserError},
+		{"wrong length", []any{"978-0-306"}, true, UserError},
+		{"forced 10", []any{10, "0-306-40615-2"}, false, UserError},
+		{"forced 10 rejects 13", []any{10, "978-0-306-40615-7"}, true, UserError},
+		{"forced 13", []any{13, "978-0-306-40615-7"}, false, UserError},
+		{"forced 13 rejects 10", []any{13, "0-306-40615-2"}, true, UserError},
+		{"unknown version", []any{12, "0-306-40615-2"}, true, SystemError},
+		{"not a string", []any{306406152}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("isbn", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isbn(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("isbn(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestWithRule(t *testing.T) {
+	accept := func(params []any) error { return nil }
+	reject := func(params []any) error { return errors.New("stubbed") }
+
+	tests := []struct {
+		name       string
+		rule       string
+		stub       RuleFunc
+		params     []any
+		panics     bool
+		wantInside bool
+		wantAfter  bool
+		hasAfter   bool
+	}{
+		{"stub a built-in", "isEmail", accept, []any{"not an email"}, false, true, false, true},
+		{"restored after panic", "isEmail", accept, []any{"not an email"}, true, true, false, true},
+		{"stub a failure", "notEmpty", reject, []any{"x"}, false, false, true, true},
+		{"new rule removed after", "stubOnly", accept, []any{"x"}, false, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			passes := func() bool {
+				ctx := v.newContext()
+				return ctx.Check(tt.rule, tt.params...).Err() == nil
+			}
+
+			func() {
+				defer func() {
+					if r := recover(); (r != nil) != tt.panics {
+						t.Fatalf("recovered %v", r)
+					}
+				}()
+
+				v.WithRule(tt.rule, tt.stub, func() {
+					if got := passes(); got != tt.wantInside {
+						t.Errorf("inside: passed = %v, want %v", got, tt.wantInside)
+					}
+					if tt.panics {
+						panic("body failed")
+					}
+				})
+			}()
+
+			if got := v.HasRule(tt.rule); got != tt.hasAfter {
+				t.Fatalf("after: HasRule = %v, want %v", got, tt.hasAfter)
+			}
+			if got := passes(); got != tt.wantAfter {
+				t.Errorf("after: passed = %v, want %v", got, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestIsPercentEncoded(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"escaped space", []any{"a%20b"}, false, UserError},
+		{"no escapes", []any{"plain"}, false, UserError},
+		{"empty", []any{""}, false, UserError},
+		{"lowercase hex", []any{"%e2%82%ac"}, false, UserError},
+		{"truncated escape", []any{"a%2"}, true, UserError},
+		{"not hex", []any{"a%zz"}, true, UserError},
+		{"lone percent", []any{"100%"}, true, UserError},
+		{"second value bad", []any{"a%20b", "a%zz"}, true, UserError},
+		{"not a string", []any{42}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("isPercentEncoded", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("isPercentEncoded(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("isPercentEncoded(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestIncreasing(t *testing.T) {
+	tests := []struct {
+		name          string
+		params        []any
+		increasing    string
+		nonDecreasing string
+	}{
+		{"strictly increasing", []any{1, 2.5, uint(3)}, "", ""},
+		{"single value", []any{1}, "", ""},
+		{"slice", []any{[]int{1, 2, 3}}, "", ""},
+		{"equal adjacent", []any{1, 2, 2, 3}, "increasing: parameter at position 3 (= 2) is not greater than the one before it (= 2)", ""},
+		{"decreasing", []any{3, 2}, "increasing: parameter at position 2 (= 2) is not greater than the one before it (= 3)", "nonDecreasing: parameter at position 2 (= 2) is not at least the one before it (= 3)"},
+		{"first bad pair reported", []any{1, 0, -1}, "increasing: parameter at position 2 (= 0) is not greater than the one before it (= 1)", "nonDecreasing: parameter at position 2 (= 0) is not at least the one before it (= 1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct{ rule, want string }{{"increasing", tt.increasing}, {"nonDecreasing", tt.nonDecreasing}} {
+				err := Check(c.rule, tt.params...).Err()
+				var ruleErr *RuleError
+				switch {
+				case c.want == "" && err != nil:
+					t.Errorf("%s(%v) = %v", c.rule, tt.params, err)
+				case c.want != "" && (!errors.As(err, &ruleErr) || ruleErr.Err.Error() != c.want):
+					t.Errorf("%s(%v) = %v, want %q", c.rule, tt.params, err, c.want)
+				}
+			}
+		})
+	}
+
+	if err := Check("increasing", 1, "two").Err(); err == nil || Classify(err) != SystemError {
+		t.Errorf("increasing(1, two) = %v, want a system error", err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		params    []any
+		wantErr   bool
+		wantClass Classification
+	}{
+		{"pattern string", []any{"^[a-z]+$", "abc"}, false, UserError},
+		{"compiled pattern", []any{regexp.MustCompile(`^\d{3}$`), "123"}, false, UserError},
+		{"several values", []any{"^a", "ab", "ac"}, false, UserError},
+		{"no match", []any{"^[a-z]+$", "ABC"}, true, UserError},
+		{"one value fails", []any{"^a", "ab", "b"}, true, UserError},
+		{"invalid pattern", []any{"(", "x"}, true, SystemError},
+		{"pattern of wrong type", []any{42, "x"}, true, SystemError},
+		{"value not a string", []any{"^a", 42}, true, SystemError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("matches", tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("matches(%v) = %v, wantErr %v", tt.params, err, tt.wantErr)
+			}
+			if err != nil && Classify(err) != tt.wantClass {
+				t.Errorf("matches(%v) classified as %v, want %v", tt.params, Classify(err), tt.wantClass)
+			}
+		})
+	}
+}
+
+func TestMatchesCachesPatterns(t *testing.T) {
+	v := New()
+	for i := 0; i < 2; i++ {
+		ctx := v.newContext()
+		if err := ctx.Check("matches", "^cached$", "cached").Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, ok := v.patterns.Load("^cached$")
+	if !ok {
+		t.Fatal("the pattern was not cached")
+	}
+	if second, _ := v.compilePattern("^cached$"); second != first {
+		t.Error("the pattern was compiled again")
+	}
+}
+
+func TestMapValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []any
+		wantErr string
+		wantSys bool
+	}{
+		{"all valid", []any{map[string]int{"a": 5, "b": 6}, "greaterThan", 4}, "", false},
+		{"empty map", []any{map[string]int{}, "greaterThan", 4}, "", false},
+		{"subject first rule", []any{map[int]string{1: "abc"}, "maxLength", 3}, "", false},
+		{"failing value names key", []any{map[string]int{"a": 5, "b": 1}, "greaterThan", 4}, "value at key b", false},
+		{"first failing key in order", []any{map[string]string{"z": "", "a": ""}, "notEmpty"}, "value at key a", false},
+		{"unknown rule", []any{map[string]int{"a": 1}, "noSuchRule"}, "noSuchRule", true},
+		{"not a map", []any{[]int{1}, "notEmpty"}, "expected a map", true},
+		{"rule name not a string", []any{map[string]int{"a": 1}, 7}, "for rule name", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("mapValues", tt.params...).Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got %v, want an error containing %q", err, tt.wantErr)
+			}
+			if got := Classify(err) == SystemError; got != tt.wantSys {
+				t.Errorf("system error = %v, want %v", got, tt.wantSys)
+			}
+		})
+	}
+}
+
+func TestAllowedRunes(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     []any
+		wantMsg    string
+		wantSystem bool
+	}{
+		{"all allowed", []any{"abc123", "cab321"}, "", false},
+		{"empty string", []any{"abc", ""}, "", false},
+		{"multibyte allowed", []any{"äöü", "üöä"}, "", false},
+		{"first rune rejected", []any{"abc", "xab"}, "'x' at position 1", false},
+		{"position counts runes", []any{"äöü", "ääx"}, "'x' at position 3", false},
+		{"every string is checked", []any{"abc", "abc", "abz"}, "'z' at position 3", false},
+		{"empty allowed set", []any{"", "a"}, "'a' at position 1", false},
+		{"allowed set not a string", []any{42, "a"}, "allowed runes", true},
+		{"subject not a string", []any{"abc", 42}, "unsupported type int", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check("allowedRunes", tt.params...).Err()
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("got %v, want an error containing %q", err, tt.wantMsg)
+			}
+			if got := Classify(err) == SystemError; got != tt.wantSystem {
+				t.Errorf("system error = %v, want %v", got, tt.wantSystem)
+			}
+		})
+	}
+
+	type handle struct {
+		Name string `validate:"allowedRunes=abcdefghijklmnopqrstuvwxyz_"`
+	}
+	if err := New().ValidateStruct(handle{Name: "ada_l"}); err != nil {
+		t.Errorf("unexpected error from tag: %v", err)
+	}
+	if got := failedFields(New().ValidateStruct(handle{Name: "Ada"})); len(got) != 1 || got[0] != "Name" {
+		t.Errorf("failed fields = %v, want [Name]", got)
+	}
+}
+
+func TestInBoundingBox(t *testing.T) {
+	// Boxes are minLat, minLng, maxLat, maxLng; the point comes last.
+	europe := []any{35.0, -10.0, 70.0, 40.0}
+	pacific := []any{-50, 170, 10, -150}
+
+	tests := []struct {
+		name       string
+		box        []any
+		point      []any
+		wantErr    bool
+		wantSystem bool
+	}{
+		{"inside", europe, []any{48.86, 2.35}, false, false},
+		{"on the edge", europe, []any{35, -10}, false, false},
+		{"north of the box", europe, []any{78.2, 15.6}, true, false},
+		{"west of the box", europe, []any{40.7, -74.0}, true, false},
+		{"integer coordinates", []any{0, 0, 10, 10}, []any{5, 5}, false, false},
+		{"across the antimeridian east", pacific, []any{-17.7, 178.0}, false, false},
+		{"across the antimeridian west", pacific, []any{-14.3, -170.7}, false, false},
+		{"across the antimeridian outside", pacific, []any{-33.9, 151.2}, true, false},
+		{"whole world", []any{-90, -180, 90, 180}, []any{-90, 180}, false, false},
+		{"latitude range reversed", []any{10, 0, 0, 10}, []any{5, 5}, true, true},
+		{"latitude out of range", []any{-91, 0, 10, 10}, []any{5, 5}, true, true},
+		{"longitude out of range", []any{0, 0, 10, 181}, []any{5, 5}, true, true},
+		{"not a number", []any{0, 0, 10, 10}, []any{"north", 5}, true, true},
+		{"missing the point", []any{0, 0, 10, 10}, []any{5}, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := append(slices.Clone(tt.box), tt.point...)
+			err := Check("inBoundingBox", params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("inBoundingBox(%v) = %v, want error %v", params, err, tt.wantErr)
+			}
+			if err != nil && (Classify(err) == SystemError) != tt.wantSystem {
+				t.Errorf("%v: system error = %v, want %v", err, Classify(err) == SystemError, tt.wantSystem)
+			}
+		})
+	}
+}
+
+func TestMustMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		check   func(ctx *ValidationContext)
+		wantErr string
+	}{
+		{"passes", func(ctx *ValidationContext) {
+			ctx.Field("Terms").MustMessage("must be accepted", func() bool { return true })
+		}, ""},
+		{"uses its message", func(ctx *ValidationContext) {
+			ctx.Field("Terms").MustMessage("must be accepted", func() bool { return false })
+		}, "Terms: must be accepted"},
+		{"Must keeps the generic message", func(ctx *ValidationContext) {
+			ctx.Field("Terms").Must(func() bool { return false })
+		}, "Terms: rule failed"},
+		{"skipped after a failure", func(ctx *ValidationContext) {
+			ctx.Field("Name").Check("notEmpty", "")
+			ctx.Field("Terms").MustMessage("must be accepted", func() bool {
+				panic("ran after the context failed")
+			})
+		}, "Name: required rule failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New().newContext()
+			tt.check(&ctx)
+
+			err := ctx.Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	ctx := New().newContext()
+	err := ctx.MustMessage("must be accepted", func() bool { return false }).Err()
+	var re *RuleError
+	if !errors.As(err, &re) || re.Rule != "must" || Classify(err) != UserError {
+		t.Errorf("got %#v, want a user-facing RuleError for must", err)
+	}
+}
+
+func TestUnregisterRuleAndHasRule(t *testing.T) {
+	custom := func(params []any) error { return nil }
+
+	tests := []struct {
+		name       string
+		setup      func(v *Validator) *Validator
+		rule       string
+		wantRemove bool
+		wantHas    bool
+	}{
+		{"builtin", func(v *Validator) *Validator { return v }, "notEmpty", true, false},
+		{"custom rule", func(v *Validator) *Validator {
+			RegisterRule(v, "custom", custom)
+			return v
+		}, "custom", true, false},
+		{"unknown rule", func(v *Validator) *Validator { return v }, "noSuchRule", false, false},
+		{"removing from a child hides the parent's", func(v *Validator) *Validator {
+			RegisterRule(v, "custom", custom)
+			return v.Child()
+		}, "custom", false, false},
+		{"removing a builtin from a child", func(v *Validator) *Validator { return v.Child() }, "notEmpty", true, false},
+		{"removing from a clone", func(v *Validator) *Validator {
+			RegisterRule(v, "custom", custom)
+			return v.Clone()
+		}, "custom", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.setup(New())
+			if !v.HasRule(tt.rule) && tt.wantRemove {
+				t.Fatalf("HasRule(%q) = false before removal", tt.rule)
+			}
+			if got := UnregisterRule(v, tt.rule); got != tt.wantRemove {
+				t.Errorf("UnregisterRule(%q) = %v, want %v", tt.rule, got, tt.wantRemove)
+			}
+			if got := v.HasRule(tt.rule); got != tt.wantHas {
+				t.Errorf("HasRule(%q) after removal = %v, want %v", tt.rule, got, tt.wantHas)
+			}
+
+			ctx := v.newContext()
+			var unknown *UnknownRuleError
+			if err := ctx.Check(tt.rule, "x").Err(); !errors.As(err, &unknown) {
+				t.Errorf("Check after removal = %v, want an UnknownRuleError", err)
+			}
+		})
+	}
+}
+
+func TestUnregisterRuleLeavesOthers(t *testing.T) {
+	parent := New()
+	RegisterRule(parent, "custom", func(params []any) error { return nil })
+	child := parent.Child()
+	clone := parent.Clone()
+
+	UnregisterRule(child, "custom")
+	UnregisterRule(clone, "notEmpty")
+
+	if !parent.HasRule("custom") || !parent.HasRule("notEmpty") {
+		t.Error("removing rules from a child or clone changed the parent")
+	}
+	if !clone.HasRule("custom") {
+		t.Error("removing a rule from the child changed the clone")
+	}
+	if !child.HasRule("notEmpty") {
+		t.Error("removing a rule from the clone changed the child")
+	}
+
+	// children see their parent's removals, clones do not
+	UnregisterRule(parent, "isEmail")
+	if !clone.HasRule("isEmail") {
+		t.Error("removing a rule from the parent changed its clone")
+	}
+	if child.HasRule("isEmail") {
+		t.Error("the child still has a rule removed from its parent")
+	}
+}
+
+func TestMustCheck(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		params    []any
+		wantErr   bool
+		wantPanic bool
+	}{
+		{"passes", "notEmpty", []any{"x"}, false, false},
+		{"fails", "notEmpty", []any{""}, true, false},
+		{"unknown rule", "noSuchRule", []any{"x"}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if r := recover(); (r != nil) != tt.wantPanic {
+					t.Errorf("recovered %v, want panic %v", r, tt.wantPanic)
+				}
+			}()
+
+			ctx := New().newContext()
+			err := ctx.MustCheck(tt.rule, tt.params...).Err()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Err() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEachPaths(t *testing.T) {
+	type line struct {
+		SKU string
+		Qty int
+	}
+
+	tests := []struct {
+		name  string
+		check func(ctx *ValidationContext)
+		want  []string
+	}{
+		{"slice index", func(ctx *ValidationContext) {
+			ctx.Field("Items").Each([]string{"a", "b", ""}, func(elem any, ctx *ValidationContext) {
+				ctx.Check("notEmpty", elem)
+			})
+		}, []string{"Items[2]"}},
+		{"array index", func(ctx *ValidationContext) {
+			ctx.Field("Pair").Each([2]string{"", "b"}, func(elem any, ctx *ValidationContext) {
+				ctx.Check("notEmpty", elem)
+			})
+		}, []string{"Pair[0]"}},
+		{"map keys in order", func(ctx *ValidationContext) {
+			ctx.Field("Map").Each(map[string]string{"b": "", "a": "", "c": "x"}, func(elem any, ctx *ValidationContext) {
+				ctx.Check("notEmpty", elem)
+			})
+		}, []string{"Map[a]", "Map[b]"}},
+		{"field inside an element", func(ctx *ValidationContext) {
+			ctx.Field("Lines").Each([]line{{"A1", 1}, {"", 0}}, func(elem any, ctx *ValidationContext) {
+				l := elem.(line)
+				ctx.Field("SKU").Check("notEmpty", l.SKU)
+				ctx.Field("Qty").Check("greaterThan", 0, l.Qty)
+			})
+		}, []string{"Lines[1].SKU", "Lines[1].Qty"}},
+		{"nested Each", func(ctx *ValidationContext) {
+			ctx.Field("Grid").Each([][]int{{1, 2}, {3, -1}}, func(row any, ctx *ValidationContext) {
+				ctx.Each(row, func(cell any, ctx *ValidationContext) {
+					ctx.Check("greaterThan", 0, cell)
+				})
+			})
+		}, []string{"Grid[1][1]"}},
+		{"Key reports the index or key", func(ctx *ValidationContext) {
+			ctx.Field("Map").Each(map[string]int{"x": 1, "y": 2}, func(elem any, ctx *ValidationContext) {
+				key := ctx.Key().(string)
+				ctx.Must(func() bool { return key != "y" })
+			})
+		}, []string{"Map[y]"}},
+		{"field is restored after Each", func(ctx *ValidationContext) {
+			ctx.Field("Items").Each([]string{""}, func(elem any, ctx *ValidationContext) {
+				ctx.Check("notEmpty", elem)
+			}).Check("notEmpty", "")
+		}, []string{"Items[0]", "Items"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New(WithCollectAll()).newContext()
+			tt.check(&ctx)
+
+			if got := failedFields(ctx.Err()); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	ctx := New().newContext()
+	if ctx.Key() != nil {
+		t.Errorf("Key() outside Each = %v, want nil", ctx.Key())
+	}
+}
+
+func TestBeforeAfterNow(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	v := New(WithClock(func() time.Time { return now }))
+
+	tests := []struct {
+		rule       string
+		params     []any
+		wantErr    bool
+		wantSystem bool
+	}{
+		{"before", []any{now.Add(-time.Second)}, false, false},
+		{"before", []any{now}, true, false},
+		{"before", []any{now.Add(time.Second)}, true, false},
+		{"after", []any{now.Add(time.Second)}, false, false},
+		{"after", []any{now}, true, false},
+		{"after", []any{now.Add(-time.Second)}, true, false},
+		{"before", []any{"now", now.Add(-time.Second)}, false, false},
+		{"after", []any{"now", now.Add(-time.Second)}, true, false},
+		{"after", []any{"now", now.Add(time.Hour), now.Add(time.Minute)}, false, false},
+		{"after", []any{"now", now.Add(time.Hour), now}, true, false},
+		{"before", []any{now.Add(time.Hour), now.Add(time.Minute)}, false, false},
+		{"after", []any{now.Add(time.Hour), now.Add(time.Minute)}, true, false},
+		{"before", []any{"now", 5}, true, true},
+		{"before", []any{"tomorrow"}, true, true},
+	}
+
+	for _, tt := range tests {
+		ctx := v.newContext()
+		err := ctx.Check(tt.rule, tt.params...).Err()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s(%v) = %v, want error %v", tt.rule, tt.params, err, tt.wantErr)
+			continue
+		}
+		if err != nil && (Classify(err) == SystemError) != tt.wantSystem {
+			t.Errorf("%s(%v): system error = %v, want %v", tt.rule, tt.params, Classify(err) == SystemError, tt.wantSystem)
+		}
+	}
+}
+
+func TestCheckEach(t *testing.T) {
+	tests := []struct {
+		name       string
+		rule       string
+		collection any
+		extra      []any
+		want       []string
+	}{
+		{"all pass", "notEmpty", []string{"a", "b"}, nil, nil},
+		{"failing elements", "notEmpty", []string{"a", "", "c", ""}, nil, []string{"Tags[1]", "Tags[3]"}},
+		{"extra params first", "greaterThan", []int{5, 1, 7}, []any{3}, []string{"Tags[1]"}},
+		{"subject first rule", "maxLength", []string{"go", "rust"}, []any{3}, []string{"Tags[1]"}},
+		{"map keys", "notEmpty", map[string]string{"en": "", "fr": "x", "de": ""}, nil, []string{"Tags[de]", "Tags[en]"}},
+		{"empty", "notEmpty", []string{}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := New(WithCollectAll()).newContext()
+			ctx.Field("Tags").CheckEach(tt.rule, tt.collection, tt.extra...)
+
+			if got := failedFields(ctx.Err()); !slices.Equal(got, tt.want) {
+				t.Errorf("failed fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	ctx := New().newContext()
+	ctx.Field("Tags").CheckEach("notEmpty", []string{"", ""})
+	if got := failedFields(ctx.Err()); !slices.Equal(got, []string{"Tags[0]"}) {
+		t.Errorf("without CollectAll, failed fields = %v, want [Tags[0]]", got)
+	}
+	ctx = New().newContext()
+	if err := ctx.CheckEach("notEmpty", "not a collection").Err(); Classify(err) != SystemError {
+		t.Errorf("CheckEach on a string = %v, want a system error", err)
+	}
+}
+
+func TestNot(t *testing.T) {
+	v := New()
+	v.AddTranslation("fr", "not isEmail", "ne doit pas être une adresse e-mail")
+	RegisterRule(v, "panics", func(params []any) error { panic("boom") })
+
+	tests := []struct {
+		name       string
+		locale     string
+		rule       string
+		params     []any
+		wantMsg    string
+		wantSystem bool
+	}{
+		{"rule fails, so Not passes", "", "isEmail", []any{"ada"}, "", false},
+		{"rule passes, so Not fails", "", "isEmail", []any{"ada@example.com"}, "Username: must not pass isEmail", false},
+		{"translated message", "fr", "isEmail", []any{"ada@example.com"}, "Username: ne doit pas être une adresse e-mail", false},
+		{"unknown rule still fails", "", "noSuchRule", []any{"ada"}, "noSuchRule", true},
+		{"panic still fails", "", "panics", []any{"ada"}, "panics", true},
+		{"bad params still fail", "", "greaterThan", []any{"ada"}, "greaterThan", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := v.newContext()
+			err := ctx.Locale(tt.locale).Field("Username").Not(tt.rule, tt.params...).Err()
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantMsg) {
+				t.Fatalf("got %v, want an error containing %q", err, tt.wantMsg)
+			}
+			if got := Classify(err) == SystemError; got != tt.wantSystem {
+				t.Errorf("system error = %v, want %v", got, tt.wantSystem)
+			}
+			if !tt.wantSystem {
+				var re *RuleError
+				if !errors.As(err, &re) || re.Code != "not_"+ruleCode(tt.rule) {
+					t.Errorf("got %#v, want a RuleError with code not_%s", err, ruleCode(tt.rule))
+				}
+			}
+		})
+	}
+
+	ctx := v.newContext()
+	ctx.plan = &Plan{}
+	ctx.Not("isEmail", "x")
+	if got := *ctx.plan; len(got) != 1 || got[0].Rule != "not isEmail" {
+		t.Errorf("planned %+v, want a single not isEmail check", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	fields := func(errs []error) []string {
+		var got []string
+		for _, err := range errs {
+			got = append(got, failedFields(err)...)
+		}
+		return got
+	}
+
+	t.Run("default mode stops at the first failure", func(t *testing.T) {
+		ctx := New().newContext()
+		ctx.Field("Name").Check("notEmpty", "")
+		ctx.Field("Email").Check("isEmail", "x")
+
+		if got := fields(ctx.Errors()); !slices.Equal(got, []string{"Name"}) {
+			t.Fatalf("got %v, want [Name]", got)
+		}
+	})
+
+	t.Run("default mode without failures", func(t *testing.T) {
+		ctx := New().newContext()
+		ctx.Field("Name").Check("notEmpty", "Ada")
+
+		if got := ctx.Errors(); got != nil {
+			t.Fatalf("got %v, want nil", got)
+		}
+	})
+
+	t.Run("collect all keeps check order", func(t *testing.T) {
+		ctx := New().newContext()
+		ctx.CollectAll()
+		ctx.Field("Email").Check("isEmail", "x")
+		ctx.Field("Name").Check("notEmpty", "")
+		ctx.Field("Age").Check("greaterThan", 17, 3)
+
+		if got := fields(ctx.Errors()); !slices.Equal(got, []string{"Email", "Name", "Age"}) {
+			t.Fatalf("got %v, want [Email Name Age]", got)
+		}
+		if got := failedFields(ctx.Err()); !slices.Equal(got, []string{"Email", "Name", "Age"}) {
+			t.Errorf("Err() reports %v, want [Email Name Age]", got)
+		}
+	})
+
+	t.Run("returned slice is a copy", func(t *testing.T) {
+		ctx := New().newContext()
+		ctx.CollectAll()
+		ctx.Field("Name").Check("notEmpty", "")
+
+		ctx.Errors()[0] = nil
+		if got := fields(ctx.Errors()); !slices.Equal(got, []string{"Name"}) {
+			t.Fatalf("got %v after modifying the returned slice, want [Name]", got)
+		}
+	})
+}
+
+type collectedSignup struct {
+	Email    string `validate:"isEmail"`
+	Name     string `validate:"notEmpty"`
+	Password string `validate:"minLength=8"`
+}
+
+func TestWithCollectAll(t *testing.T) {
+	signup := collectedSignup{Email: "x", Password: "short"}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		wantFields []string
+	}{
+		{"default stops at first failure", nil, []string{"Email"}},
+		{"collects in declaration order", []Option{WithCollectAll()}, []string{"Email", "Name", "Password"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New(tt.opts...)
+			if got := failedFields(v.ValidateStruct(signup)); !slices.Equal(got, tt.wantFields) {
+				t.Errorf("ValidateStruct failed %v, want %v", got, tt.wantFields)
+			}
+
+			ctx := v.newContext()
+			ctx.Validate(signup)
+			var got []string
+			for _, err := range ctx.Errors() {
+				got = append(got, failedFields(err)...)
+			}
+			if !slices.Equal(got, tt.wantFields) {
+				t.Errorf("Errors() failed %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
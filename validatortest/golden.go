@@ -0,0 +1,120 @@
+// Package validatortest provides helpers for testing code that uses the
+// validator package.
+package validatortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	validator "github.com/CColeson/NoBSGoValidator"
+)
+
+// Update makes Golden rewrite golden files instead of comparing with them.
+// It is set when the VALIDATORTEST_UPDATE environment variable is 1, and
+// tests may also set it from a flag of their own.
+var Update = os.Getenv("VALIDATORTEST_UPDATE") == "1"
+
+var (
+	timestampPattern = regexp.MustCompile(`\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?`)
+	durationPattern  = regexp.MustCompile(`\b(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`)
+)
+
+// Golden validates value and compares the rendered errors with goldenFile.
+// Run the tests with VALIDATORTEST_UPDATE=1 to rewrite the golden file.
+func Golden(t testing.TB, v *validator.Validator, value any, goldenFile string) {
+	t.Helper()
+
+	actual, err := Render(v.ResultOf(v.ValidateStruct(value)))
+	if err != nil {
+		t.Fatalf("validatortest: rendering errors: %v", err)
+	}
+
+	if Update {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o755); err != nil {
+			t.Fatalf("validatortest: %v", err)
+		}
+
+		if err := os.WriteFile(goldenFile, actual, 0o644); err != nil {
+			t.Fatalf("validatortest: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatalf("validatortest: %v (run with VALIDATORTEST_UPDATE=1 to create it)", err)
+	}
+
+	if string(expected) != string(actual) {
+		t.Errorf("validatortest: errors for %T differ from %s:\n%s", value, goldenFile, diff(string(expected), string(actual)))
+	}
+}
+
+// Render renders result as indented JSON, with timestamps and durations
+// normalized so the output is stable.
+func Render(result validator.Result) ([]byte, error) {
+	compact, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact, "", "  "); err != nil {
+		return nil, err
+	}
+	out.WriteByte('\n')
+
+	return []byte(normalize(out.String())), nil
+}
+
+func normalize(message string) string {
+	message = timestampPattern.ReplaceAllString(message, "<timestamp>")
+	return durationPattern.ReplaceAllString(message, "<duration>")
+}
+
+// diff renders a line-based unified diff of expected and actual.
+func diff(expected string, actual string) string {
+	a := strings.Split(expected, "\n")
+	b := strings.Split(actual, "\n")
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("--- golden\n+++ actual\n")
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			sb.WriteString(" " + a[i] + "\n")
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+" + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("-" + a[i] + "\n")
+			i++
+		}
+	}
+
+	return sb.String()
+}
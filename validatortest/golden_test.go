@@ -0,0 +1,88 @@
+package validatortest
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	validator "github.com/CColeson/NoBSGoValidator"
+)
+
+// a test binary defining its own -update flag must not clash with the package
+var _ = flag.Bool("update", false, "update golden files")
+
+type signup struct {
+	Email string `validate:"isEmail"`
+	Name  string `validate:"notEmpty"`
+	Age   int    `validate:"greaterThan=17"`
+}
+
+// recorder captures failures reported to it instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestGolden(t *testing.T) {
+	tests := []struct {
+		name  string
+		v     *validator.Validator
+		value signup
+		file  string
+	}{
+		{"valid", validator.New(), signup{Email: "ada@example.com", Name: "Ada", Age: 36}, "valid.golden"},
+		{"single error", validator.New(), signup{Email: "nope", Name: "Ada", Age: 36}, "single.golden"},
+		{"collect all", validator.New(validator.WithCollectAll()), signup{Email: "nope", Age: 3}, "collect_all.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			Golden(t, tt.v, tt.value, filepath.Join("testdata", tt.file))
+		})
+	}
+}
+
+func TestGoldenReportsDiff(t *testing.T) {
+	r := &recorder{TB: t}
+	Golden(r, validator.New(), signup{Email: "ada@example.com", Name: "Ada", Age: 3}, filepath.Join("testdata", "valid.golden"))
+
+	if len(r.failures) != 1 {
+		t.Fatalf("got %d failures, want 1: %v", len(r.failures), r.failures)
+	}
+	for _, want := range []string{"--- golden", "+++ actual", "-  \"errors\": []", "+      \"field\": \"Age\","} {
+		if !strings.Contains(r.failures[0], want) {
+			t.Errorf("diff does not contain %q:\n%s", want, r.failures[0])
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"expired at 2024-05-01T12:00:00Z", "expired at <timestamp>"},
+		{"expired at 2024-05-01 12:00:00.123+02:00", "expired at <timestamp>"},
+		{"took 1m30.5s", "took <duration>"},
+		{"took 250ms", "took <duration>"},
+		{"at least 8 characters", "at least 8 characters"},
+	}
+
+	for _, tt := range tests {
+		if got := normalize(tt.in); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package validator
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+)
+
+// TestBuildsForWasm keeps the package and the wasm example compiling for
+// GOOS=js GOARCH=wasm, so a new dependency cannot quietly break the browser
+// build.
+func TestBuildsForWasm(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping the wasm build in short mode")
+	}
+
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	cmd := exec.Command(gobin, "build", "-o", os.DevNull, "./examples/wasm")
+	cmd.Env = append(os.Environ(), "GOOS=js", "GOARCH=wasm")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("GOOS=js GOARCH=wasm go build ./examples/wasm: %v\n%s", err, out)
+	}
+}